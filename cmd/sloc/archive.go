@@ -0,0 +1,188 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// archiveSuffixes are the names of the archives whose entries are counted
+// as files, unless -no-archives is given.
+var archiveSuffixes = []string{".zip", ".jar", ".whl", ".tar", ".tar.gz", ".tgz"}
+
+// isArchive reports whether the file name is an archive to count the
+// entries of.
+func isArchive(name string) bool {
+	if *noArchives {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, s := range archiveSuffixes {
+		if strings.HasSuffix(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isZip reports whether the archive name is in the zip format, as jars
+// and Python wheels are.
+func isZip(name string) bool {
+	switch path.Ext(strings.ToLower(name)) {
+	case ".zip", ".jar", ".whl":
+		return true
+	}
+	return false
+}
+
+// countArchives counts the entries of the archives found by the walk.
+func (w *walker) countArchives(c *sloc.Counter) {
+	for _, n := range w.archives {
+		var err error
+		if isZip(n) {
+			err = w.countZip(c, n)
+		} else {
+			err = w.countTar(c, n)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  ! %s\n", err.Error())
+			fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+		}
+	}
+}
+
+// entryPath returns the name of an archive entry as a relative path.
+func entryPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// noslocDirs returns the directories among names that hold a .nosloc.
+func noslocDirs(names []string) map[string]bool {
+	nosloc := map[string]bool{}
+	for _, n := range names {
+		if path.Base(n) == ".nosloc" {
+			nosloc[path.Dir(n)] = true
+		}
+	}
+	return nosloc
+}
+
+// countEntry counts the entry at rel, of size bytes, of the archive n,
+// unless walking the entries as a tree would have left it out. Archives
+// within archives are skipped.
+func (w *walker) countEntry(c *sloc.Counter, n, rel string, size int64, nosloc map[string]bool, open func() (io.ReadCloser, error)) error {
+	if w.leftOut(rel, nosloc) {
+		return nil
+	}
+	if isArchive(rel) {
+		fmt.Fprintf(os.Stderr, "  ! skipped, archives within archives are not counted\n")
+		fmt.Fprintf(os.Stderr, "  ! %s!%s\n", n, rel)
+		return nil
+	}
+	if maxFileSize > 0 && size > int64(maxFileSize) {
+		fmt.Fprintf(os.Stderr, "  ! skipped, %d bytes is over -max-file-size\n", size)
+		fmt.Fprintf(os.Stderr, "  ! %s!%s\n", n, rel)
+		return nil
+	}
+	r, err := open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return c.CountEntry(displayPath(n), rel, r)
+}
+
+// countZip counts the regular files in the zip archive n.
+func (w *walker) countZip(c *sloc.Counter, n string) error {
+	zr, err := zip.OpenReader(n)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, entryPath(f.Name))
+	}
+	nosloc := noslocDirs(names)
+	for i, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		if err := w.countEntry(c, n, names[i], int64(f.UncompressedSize64), nosloc, f.Open); err != nil {
+			return fmt.Errorf("%s: %s", names[i], err)
+		}
+	}
+	return nil
+}
+
+// openTar opens the tar archive n, which is compressed with gzip if it is
+// named so.
+func openTar(n string) (*tar.Reader, io.Closer, error) {
+	f, err := os.Open(n)
+	if err != nil {
+		return nil, nil, err
+	}
+	l := strings.ToLower(n)
+	if !strings.HasSuffix(l, ".gz") && !strings.HasSuffix(l, ".tgz") {
+		return tar.NewReader(f), f, nil
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return tar.NewReader(gz), f, nil
+}
+
+// countTar counts the regular files in the tar archive n. The archive is
+// read twice, first for the .nosloc files and then for the rest.
+func (w *walker) countTar(c *sloc.Counter, n string) error {
+	tr, f, err := openTar(n)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return err
+		}
+		names = append(names, entryPath(h.Name))
+	}
+	f.Close()
+	nosloc := noslocDirs(names)
+
+	if tr, f, err = openTar(n); err != nil {
+		return err
+	}
+	defer f.Close()
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !h.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		rel := entryPath(h.Name)
+		open := func() (io.ReadCloser, error) { return ioutil.NopCloser(tr), nil }
+		if err := w.countEntry(c, n, rel, h.Size, nosloc, open); err != nil {
+			return fmt.Errorf("%s: %s", rel, err)
+		}
+	}
+}
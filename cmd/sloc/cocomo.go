@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// A cocomoEstimate is the COCOMO model's estimate for a code base, by
+// default the basic model's for organic mode: a small team with good
+// experience working on familiar, less than rigid requirements.
+type cocomoEstimate struct {
+	Model        string  `json:"model"`
+	Mode         string  `json:"mode"`
+	CodeLines    int     `json:"code_lines"`
+	PersonMonths float64 `json:"person_months"`
+	Months       float64 `json:"schedule_months"`
+	Developers   float64 `json:"developers"`
+	Cost         float64 `json:"cost"`
+	Salary       float64 `json:"salary"`
+	Overhead     float64 `json:"overhead"`
+	EAF          float64 `json:"eaf"`
+}
+
+// A cocomoCoeffs holds the coefficients of a development mode: effort is
+// a×KLOC^b person-months, with a for the basic or the intermediate model,
+// and the schedule c×effort^d months.
+type cocomoCoeffs struct {
+	basicA, intermediateA, b, c, d float64
+}
+
+// cocomoModes are the development modes, after Boehm. Organic mode with
+// the basic model is what sloccount uses.
+var cocomoModes = map[string]cocomoCoeffs{
+	"organic":       {2.4, 3.2, 1.05, 2.5, 0.38},
+	"semi-detached": {3.0, 3.0, 1.12, 2.5, 0.35},
+	"embedded":      {3.6, 2.8, 1.20, 2.5, 0.32},
+}
+
+// estimateCocomo applies the model, basic or intermediate, in the
+// development mode to the given number of code lines. The salary is per
+// year and per developer; overhead multiplies it to account for everything
+// else a developer costs. The effort adjustment factor eaf multiplies the
+// effort of either model.
+func estimateCocomo(code int, model, mode string, salary, overhead, eaf float64) cocomoEstimate {
+	e := cocomoEstimate{Model: model, Mode: mode, CodeLines: code, Salary: salary, Overhead: overhead, EAF: eaf}
+	if code <= 0 {
+		return e
+	}
+	m := cocomoModes[mode]
+	a := m.basicA
+	if model == "intermediate" {
+		a = m.intermediateA
+	}
+	e.PersonMonths = a * math.Pow(float64(code)/1000, m.b) * eaf
+	e.Months = m.c * math.Pow(e.PersonMonths, m.d)
+	e.Developers = e.PersonMonths / e.Months
+	e.Cost = e.PersonMonths / 12 * salary * overhead
+	return e
+}
+
+func (e cocomoEstimate) print() {
+	fmt.Println()
+	fmt.Printf("COCOMO, %s model, %s mode\n", e.Model, e.Mode)
+	fmt.Printf("Estimated effort:   %.2f person-months (%.2f person-years)\n", e.PersonMonths, e.PersonMonths/12)
+	fmt.Printf("Estimated schedule: %.2f months (%.2f years)\n", e.Months, e.Months/12)
+	fmt.Printf("Estimated team:     %.2f developers\n", e.Developers)
+	fmt.Printf("Estimated cost:     $%.0f (salary $%.0f/year, overhead %.2f, EAF %.2f)\n", e.Cost, e.Salary, e.Overhead, e.EAF)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateCocomo(t *testing.T) {
+	// Worked by hand from 10 KLOC, for which effort is a×10^b and the
+	// schedule 2.5×effort^d.
+	tests := []struct {
+		model, mode  string
+		code         int
+		eaf          float64
+		personMonths float64
+		months       float64
+		developers   float64
+		cost         float64
+	}{
+		{"basic", "organic", 10000, 1, 26.9284, 8.7382, 3.0817, 303138.87},
+		{"basic", "semi-detached", 10000, 1, 39.5477, 9.0559, 4.3671, 445196.39},
+		{"basic", "embedded", 10000, 1, 57.0562, 9.1192, 6.2567, 642292.55},
+		{"intermediate", "organic", 10000, 1, 35.9046, 9.7476, 3.6834, 404185.16},
+		{"intermediate", "semi-detached", 10000, 1, 39.5477, 9.0559, 4.3671, 445196.39},
+		{"intermediate", "embedded", 10000, 1, 44.3770, 8.4145, 5.2738, 499560.87},
+		{"basic", "organic", 100000, 1, 302.1421, 21.8988, 13.7972, 3401274.03},
+		// The EAF multiplies the effort: 26.9284×1.5.
+		{"basic", "organic", 10000, 1.5, 40.3927, 10.1938, 3.9625, 454708.30},
+		{"basic", "organic", 0, 1, 0, 0, 0, 0},
+	}
+	near := func(got, want, tolerance float64) bool { return math.Abs(got-want) <= tolerance }
+	for _, tt := range tests {
+		e := estimateCocomo(tt.code, tt.model, tt.mode, 56286, 2.4, tt.eaf)
+		if !near(e.PersonMonths, tt.personMonths, 0.001) || !near(e.Months, tt.months, 0.001) ||
+			!near(e.Developers, tt.developers, 0.001) || !near(e.Cost, tt.cost, 0.05) {
+			t.Errorf("%s %s, %d lines, EAF %g: got %.4f person-months, %.4f months, %.4f developers, $%.2f; want %.4f, %.4f, %.4f, $%.2f",
+				tt.model, tt.mode, tt.code, tt.eaf, e.PersonMonths, e.Months, e.Developers, e.Cost,
+				tt.personMonths, tt.months, tt.developers, tt.cost)
+		}
+	}
+}
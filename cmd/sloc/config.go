@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// A config is the content of a .sloc.json or .sloc.toml file.
+type config struct {
+	// Languages are added to the table, ahead of the built-in ones; one
+	// with the name of a built-in language replaces it.
+	Languages []langConfig `json:"languages"`
+
+	// Extensions maps extensions to the languages to count them as.
+	Extensions map[string]string `json:"extensions"`
+
+	// Disable names built-in languages not to count.
+	Disable []string `json:"disable"`
+}
+
+type langConfig struct {
+	Name         string   `json:"name"`
+	Extensions   []string `json:"extensions"`
+	Filenames    []string `json:"filenames"`
+	LineComments []string `json:"line_comments"`
+	BlockStart   string   `json:"block_start"`
+	BlockEnd     string   `json:"block_end"`
+	Nesting      bool     `json:"nesting"`
+	Quotes       []string `json:"quotes"`
+}
+
+var configNames = []string{".sloc.json", ".sloc.toml"}
+
+// findConfigs returns the config files that apply to a run over root: the
+// one in the home directory, then the one in root, which takes precedence.
+func findConfigs(root string) []string {
+	var dirs, found []string
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	if fi, err := os.Stat(root); err == nil && !fi.IsDir() {
+		root = filepath.Dir(root)
+	}
+	if abs, err := filepath.Abs(root); err == nil && (len(dirs) == 0 || abs != dirs[0]) {
+		dirs = append(dirs, abs)
+	}
+	for _, d := range dirs {
+		for _, n := range configNames {
+			if f := filepath.Join(d, n); fileExists(f) {
+				found = append(found, f)
+			}
+		}
+	}
+	return found
+}
+
+func fileExists(name string) bool {
+	fi, err := os.Stat(name)
+	return err == nil && !fi.IsDir()
+}
+
+// loadConfig reads the config file fname, in TOML if its name says so and
+// JSON otherwise.
+func loadConfig(fname string) (*config, error) {
+	data, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(fname, ".toml") {
+		m, err := parseTOML(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", fname, err)
+		}
+		if data, err = json.Marshal(m); err != nil {
+			return nil, err
+		}
+	}
+	cf := &config{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(cf); err != nil {
+		if se, ok := err.(*json.SyntaxError); ok {
+			line := 1 + bytes.Count(data[:se.Offset], []byte("\n"))
+			return nil, fmt.Errorf("%s: line %d: %s", fname, line, err)
+		}
+		return nil, fmt.Errorf("%s: %s", fname, err)
+	}
+	return cf, nil
+}
+
+// language builds the language l describes, or says what is wrong with
+// it.
+func (l *langConfig) language() (sloc.Language, error) {
+	if l.Name == "" {
+		return sloc.Language{}, fmt.Errorf("no name")
+	}
+	if len(l.Extensions) == 0 && len(l.Filenames) == 0 {
+		return sloc.Language{}, fmt.Errorf("%s: no extensions or filenames", l.Name)
+	}
+	if (l.BlockStart == "") != (l.BlockEnd == "") {
+		return sloc.Language{}, fmt.Errorf("%s: block_start and block_end go together", l.Name)
+	}
+	m := sloc.Matcher{Names: l.Filenames}
+	for _, ext := range l.Extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		m.Exts = append(m.Exts, ext)
+	}
+	cm := sloc.Commenter{LineComments: l.LineComments}
+	if l.BlockStart != "" {
+		cm.Blocks = []sloc.Block{{Start: l.BlockStart, End: l.BlockEnd, Nesting: l.Nesting}}
+	}
+	for _, q := range l.Quotes {
+		if q == "" {
+			return sloc.Language{}, fmt.Errorf("%s: empty quote", l.Name)
+		}
+		cm.Strings = append(cm.Strings, sloc.Quote{Start: q, End: q, Escape: '\\'})
+	}
+	return sloc.Language{Namer: sloc.Namer(l.Name), Matcher: m, Commenter: cm}, nil
+}
+
+// apply changes the languages of c as the config file fname says.
+func (cf *config) apply(c *sloc.Counter, fname string) error {
+	var added []sloc.Language
+	replaced := map[string]bool{}
+	for i := range cf.Languages {
+		l, err := cf.Languages[i].language()
+		if err != nil {
+			return fmt.Errorf("%s: languages[%d]: %s", fname, i, err)
+		}
+		added = append(added, l)
+		replaced[l.Name()] = true
+	}
+	for _, name := range cf.Disable {
+		if _, ok := c.Lookup(name); !ok {
+			return fmt.Errorf("%s: disable: unknown language %q", fname, name)
+		}
+	}
+	var langs []sloc.Language
+	for _, l := range c.Languages {
+		if !replaced[l.Name()] && !containsFold(cf.Disable, l.Name()) {
+			langs = append(langs, l)
+		}
+	}
+	c.Languages = append(added, langs...)
+	if err := mapExtensions(c, cf.Extensions); err != nil {
+		return fmt.Errorf("%s: extensions%s", fname, err)
+	}
+	return nil
+}
+
+// mapExtensions makes c count the files with the extensions in exts as the
+// languages they map to, ahead of any other language and without
+// detection.
+func mapExtensions(c *sloc.Counter, exts map[string]string) error {
+	var mapped []sloc.Language
+	for ext, name := range exts {
+		l, ok := c.Lookup(name)
+		if !ok {
+			return fmt.Errorf("[%q]: unknown language %q", ext, name)
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		l.Matcher = sloc.Matcher{Exts: []string{ext}}
+		mapped = append(mapped, l)
+		delete(c.Detectors, ext)
+	}
+	c.Languages = append(mapped, c.Languages...)
+	return nil
+}
+
+// An extMap is a flag.Value collecting extension mappings given as
+// ext=Language, either as repeated flags or as comma-separated lists.
+type extMap map[string]string
+
+func (m extMap) String() string {
+	var s []string
+	for ext, name := range m {
+		s = append(s, ext+"="+name)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+func (m extMap) Set(v string) error {
+	for _, kv := range strings.Split(v, ",") {
+		if kv = strings.TrimSpace(kv); kv == "" {
+			continue
+		}
+		i := strings.Index(kv, "=")
+		if i <= 0 || i == len(kv)-1 {
+			return fmt.Errorf("%q is not of the form ext=Language", kv)
+		}
+		m[kv[:i]] = kv[i+1:]
+	}
+	return nil
+}
+
+var extMappings = extMap{}
+
+func containsFold(list []string, s string) bool {
+	for _, l := range list {
+		if strings.EqualFold(l, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// A baseline is the file written by -save and read by -diff.
+type baseline struct {
+	Version   string                `json:"version"`
+	Languages map[string]sloc.Stats `json:"languages"`
+}
+
+func saveBaseline(c *sloc.Counter, fname string) error {
+	bs, err := json.MarshalIndent(baseline{VERSION, c.Results()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, append(bs, '\n'), 0666)
+}
+
+func loadBaseline(fname string) (*baseline, error) {
+	bs, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	b := &baseline{}
+	if err := json.Unmarshal(bs, b); err != nil {
+		return nil, fmt.Errorf("%s: %s", fname, err)
+	}
+	return b, nil
+}
+
+// A langDelta is how much a language changed since the baseline.
+type langDelta struct {
+	Name    string `json:"name"`
+	Files   int    `json:"files"`
+	Code    int    `json:"code"`
+	Comment int    `json:"comment"`
+	Blank   int    `json:"blank"`
+	Total   int    `json:"total"`
+}
+
+func (d *langDelta) add(s sloc.Stats, sign int) {
+	d.Files += sign * s.FileCount
+	d.Code += sign * s.CodeLines
+	d.Comment += sign * s.CommentLines
+	d.Blank += sign * s.BlankLines
+	d.Total += sign * s.TotalLines
+}
+
+type diffReport struct {
+	Baseline  string      `json:"baseline"`
+	Languages []langDelta `json:"languages"`
+	Total     langDelta   `json:"total"`
+}
+
+// diffResults compares current results against a baseline. Languages
+// found on only one side are reported with their whole count.
+func diffResults(name string, old, cur map[string]sloc.Stats) *diffReport {
+	names := map[string]bool{}
+	for n := range old {
+		names[n] = true
+	}
+	for n := range cur {
+		names[n] = true
+	}
+	r := &diffReport{Baseline: name, Languages: []langDelta{}, Total: langDelta{Name: "Total"}}
+	for n := range names {
+		d := langDelta{Name: n}
+		d.add(cur[n], 1)
+		d.add(old[n], -1)
+		r.Total.add(cur[n], 1)
+		r.Total.add(old[n], -1)
+		r.Languages = append(r.Languages, d)
+	}
+	sort.Sort(byDelta(r.Languages))
+	return r
+}
+
+type byDelta []langDelta
+
+func (d byDelta) Len() int { return len(d) }
+
+func (d byDelta) Less(i, j int) bool {
+	if d[i].Code == d[j].Code {
+		return d[i].Name < d[j].Name
+	}
+	return d[i].Code > d[j].Code
+}
+
+func (d byDelta) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
+
+func (r *diffReport) print() {
+	fmt.Printf("\nChanges since %s:\n", r.Baseline)
+	w := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Language\tFiles\tCode\tComment\tBlank\tTotal\t")
+	for _, d := range r.Languages {
+		fmt.Fprintf(w, "%s\t%+d\t%+d\t%+d\t%+d\t%+d\t\n", d.Name, d.Files, d.Code, d.Comment, d.Blank, d.Total)
+	}
+	w.Flush()
+	t := r.Total
+	fmt.Printf("%+d files, %+d code, %+d comment, %+d blank, %+d total lines\n", t.Files, t.Code, t.Comment, t.Blank, t.Total)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// jsonDuplicate is a group of files with the same content in JSON reports.
+// The paths of empty files are left out.
+type jsonDuplicate struct {
+	Files int      `json:"files"`
+	Lines int      `json:"lines"` // of all the copies
+	Empty bool     `json:"empty,omitempty"`
+	Paths []string `json:"paths,omitempty"`
+}
+
+func newJSONDuplicates(dups []sloc.Duplicate) []jsonDuplicate {
+	r := []jsonDuplicate{}
+	for _, d := range dups {
+		j := jsonDuplicate{Files: len(d.Paths), Lines: d.Lines(), Empty: d.TotalLines == 0}
+		if !j.Empty {
+			j.Paths = d.Paths
+		}
+		r = append(r, j)
+	}
+	return r
+}
+
+// deduplicatedLines returns how many lines the copies of files that
+// -skip-duplicates left out held.
+func deduplicatedLines(dups []sloc.Duplicate) int {
+	n := 0
+	for _, d := range dups {
+		n += d.TotalLines * (len(d.Paths) - 1)
+	}
+	return n
+}
+
+// printDuplicates prints the groups of files with the same content if
+// -duplicates is set.
+func printDuplicates(c *sloc.Counter) {
+	if !*duplicates {
+		return
+	}
+	fmt.Println()
+	dups := c.Duplicates()
+	if len(dups) == 0 {
+		fmt.Println("No duplicate files.")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', 0)
+	fmt.Fprint(w, tabRow("Files", "Lines", "Duplicates"))
+	for _, d := range dups {
+		paths := strings.Join(d.Paths, ", ")
+		if d.TotalLines == 0 {
+			paths = "(empty)"
+		}
+		fmt.Fprint(w, tabRow(strconv.Itoa(len(d.Paths)), strconv.Itoa(d.Lines()), paths))
+	}
+	w.Flush()
+}
+
+// noteDuplicates notes on stderr what -skip-duplicates left out.
+func noteDuplicates(c *sloc.Counter) {
+	if !*skipDups || *quiet {
+		return
+	}
+	dups := c.Duplicates()
+	files := 0
+	for _, d := range dups {
+		files += len(d.Paths) - 1
+	}
+	if files > 0 {
+		fmt.Fprintf(os.Stderr, "  skipped %d duplicate files, %d lines; use -duplicates to list them\n", files, deduplicatedLines(dups))
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+func TestSkipDuplicatesWorkers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sloc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var files []string
+	for _, n := range []string{"z/a.js", "y/a.ts", "x/a.jsx", "w/a.tsx", "v/b.js", "u/b.ts", "t/b.jsx", "s/b.tsx"} {
+		p := filepath.Join(dir, n)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte("// A.\nexport const a = 1;\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, p)
+	}
+
+	want := filepath.Join(dir, "s/b.tsx")
+	for run := 0; run < 20; run++ {
+		c := sloc.NewCounter()
+		c.SkipDuplicates, c.KeepFiles = true, true
+		count(c, files, 4)
+		if f := c.Files(); len(f) != 1 || f[0].Path != want {
+			t.Fatalf("-j 4, run %d: counted %v, want %s alone", run, f, want)
+		}
+		if d := c.Duplicates(); len(d) != 1 || len(d[0].Paths) != len(files) || deduplicatedLines(d) != 2*(len(files)-1) {
+			t.Fatalf("-j 4, run %d: got groups %+v", run, d)
+		}
+	}
+}
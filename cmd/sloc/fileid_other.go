@@ -0,0 +1,12 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package main
+
+import "os"
+
+// fileID is not available here, so files reached through several links
+// are counted for each.
+func fileID(fi os.FileInfo) (fileKey, bool) {
+	return fileKey{}, false
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+)
+
+// A patternList is a flag.Value collecting glob patterns, given either as
+// repeated flags or as comma-separated lists.
+type patternList [][]string
+
+func (p *patternList) String() string {
+	var s []string
+	for _, segs := range *p {
+		s = append(s, strings.Join(segs, "/"))
+	}
+	return strings.Join(s, ",")
+}
+
+func (p *patternList) Set(v string) error {
+	for _, pat := range strings.Split(v, ",") {
+		if pat = strings.TrimSpace(pat); pat != "" {
+			*p = append(*p, compileGlob(pat))
+		}
+	}
+	return nil
+}
+
+// Match reports whether rel matches any of the patterns. A directory can
+// also be matched by a pattern for everything inside it, so that "vendor/**"
+// prunes vendor itself.
+func (p patternList) Match(rel string, isDir bool) bool {
+	name := strings.Split(rel, "/")
+	for _, segs := range p {
+		if matchSegments(segs, name) {
+			return true
+		}
+		if isDir && len(segs) > 1 && segs[len(segs)-1] == "**" && matchSegments(segs[:len(segs)-1], name) {
+			return true
+		}
+	}
+	return false
+}
+
+var excludes, includes patternList
+
+// excluded reports whether rel, a path relative to a root argument, is
+// filtered out by -exclude or -include. Archives are left to -include to
+// filter entry by entry.
+func (w *walker) excluded(rel string, isDir bool) bool {
+	if excludes.Match(rel, isDir) || (!isDir && len(includes) > 0 && !includes.Match(rel, false) && !isArchive(rel)) {
+		if isDir {
+			w.skippedDirs++
+		} else {
+			w.skippedFiles++
+		}
+		return true
+	}
+	return false
+}
+
+// relPath returns p relative to root, which is one of its ancestors in
+// the walk.
+func relPath(root, p string) string {
+	if root == "." {
+		return p
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(p, root), "/")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// gitFiles returns the paths, relative to dir, of the files git tracks
+// there and under the pathspecs, if any. The submodules are left out
+// unless submodules is set, and then their files are in.
+func gitFiles(dir string, submodules bool, pathspecs ...string) ([]string, error) {
+	if err := exec.Command("git", "-C", dir, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return nil, fmt.Errorf("%s is not in a git work tree", dir)
+	}
+	args := []string{"-C", dir, "ls-files", "-z", "--stage"}
+	if submodules {
+		args = append(args, "--recurse-submodules")
+	}
+	out, err := exec.Command("git", append(append(args, "--"), pathspecs...)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files in %s: %s", dir, err)
+	}
+	var files []string
+	for _, e := range strings.Split(string(out), "\x00") {
+		// Entries are "<mode> <object> <stage>\t<path>", with a path
+		// listed once for each stage while a merge is in conflict.
+		tab := strings.IndexByte(e, '\t')
+		if tab < 0 {
+			continue
+		}
+		p := e[tab+1:]
+		if strings.HasPrefix(e, "160000 ") || (len(files) > 0 && files[len(files)-1] == p) {
+			continue
+		}
+		files = append(files, p)
+	}
+	return files, nil
+}
+
+// addGit adds the files git tracks under n, a file or a directory.
+func (w *walker) addGit(n string) error {
+	dir, pathspecs := n, []string(nil)
+	if fi, err := os.Stat(n); err != nil {
+		return err
+	} else if !fi.IsDir() {
+		dir, pathspecs = path.Dir(n), []string{path.Base(n)}
+	}
+	files, err := gitFiles(dir, *gitSubs, pathspecs...)
+	if err != nil {
+		return err
+	}
+	nosloc := map[string]bool{} // the directories holding a .nosloc
+	for _, f := range files {
+		if path.Base(f) == ".nosloc" {
+			nosloc[path.Dir(f)] = true
+		}
+	}
+	for _, f := range files {
+		w.addTracked(path.Join(dir, f), f, nosloc)
+	}
+	return nil
+}
+
+// leftOut reports whether walking a tree would have left out the file at
+// rel in it: for being in a hidden, ignored by default, excluded or nosloc
+// directory, hidden itself or excluded.
+func (w *walker) leftOut(rel string, nosloc map[string]bool) bool {
+	if nosloc["."] {
+		return true
+	}
+	elems := strings.Split(rel, "/")
+	for i, e := range elems[:len(elems)-1] {
+		d := strings.Join(elems[:i+1], "/")
+		if e[0] == '.' || (!*noDefIgnores && defaultIgnores[e]) || excludes.Match(d, true) || nosloc[d] {
+			return true
+		}
+	}
+	if base := elems[len(elems)-1]; base[0] == '.' && !w.dotfiles[base] {
+		return true
+	}
+	return w.excluded(rel, false)
+}
+
+// addTracked adds the tracked file n, at rel in the tree given, unless
+// leftOut says otherwise. The files deleted from the work tree but not
+// from the index are left out too.
+func (w *walker) addTracked(n, rel string, nosloc map[string]bool) {
+	if w.leftOut(rel, nosloc) {
+		return
+	}
+	fi, err := os.Lstat(n)
+	if err != nil {
+		if *verbose {
+			fmt.Fprintf(os.Stderr, "  %s: deleted but not staged, skipped\n", n)
+		}
+		return
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		if !*followLinks {
+			w.skippedLinks++
+			return
+		}
+		if fi, err = os.Stat(n); err != nil {
+			fmt.Fprintf(os.Stderr, "  ! broken symlink: %s\n", err)
+			fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+			return
+		}
+	}
+	if fi.Mode()&os.ModeType == 0 {
+		w.addFile(n, fi)
+	}
+}
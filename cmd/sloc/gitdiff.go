@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// A numstat is what git diff --numstat says of a file: its paths, two
+// for a renamed file, and whether it is binary.
+type numstat struct {
+	binary   bool
+	old, new string
+}
+
+// gitNumstat returns the files that changed between the revisions from
+// and to under dir, with renames followed.
+func gitNumstat(dir, from, to string) ([]numstat, error) {
+	out, err := exec.Command("git", "-C", dir, "diff", "--numstat", "-z", "-M", "--relative", from, to, "--").Output()
+	if err != nil {
+		return nil, gitError("git diff", err)
+	}
+	// Records are "added\tremoved\tpath\0", or for a rename
+	// "added\tremoved\t\0old\0new\0".
+	fields := strings.Split(string(out), "\x00")
+	var stats []numstat
+	for i := 0; i < len(fields); i++ {
+		f := strings.SplitN(fields[i], "\t", 3)
+		if len(f) != 3 {
+			continue
+		}
+		s := numstat{binary: f[0] == "-", old: f[2], new: f[2]}
+		if f[2] == "" && i+2 < len(fields) {
+			s.old, s.new = fields[i+1], fields[i+2]
+			i += 2
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// gitHunks returns the numbers, from 1, of the lines of the file s
+// removed from its old version at from and added to its new version at to.
+func gitHunks(dir, from, to string, s numstat) (removed, added []int, err error) {
+	args := []string{"--literal-pathspecs", "-C", dir, "diff", "-U0", "--no-color", "--no-ext-diff", "-M", "--relative", from, to, "--", s.old}
+	if s.new != s.old {
+		args = append(args, s.new)
+	}
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, nil, gitError("git diff", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		// Hunks start "@@ -start,count +start,count @@", where a count
+		// of 1 may be left out.
+		f := strings.Fields(line)
+		if len(f) < 4 || f[0] != "@@" || f[3] != "@@" {
+			continue
+		}
+		removed = appendRange(removed, strings.TrimPrefix(f[1], "-"))
+		added = appendRange(added, strings.TrimPrefix(f[2], "+"))
+	}
+	return removed, added, nil
+}
+
+// appendRange appends to lines the numbers of the hunk range r, given as
+// "start,count" or "start".
+func appendRange(lines []int, r string) []int {
+	count := 1
+	if i := strings.IndexByte(r, ','); i >= 0 {
+		count, _ = strconv.Atoi(r[i+1:])
+		r = r[:i]
+	}
+	start, _ := strconv.Atoi(r)
+	for n := start; n < start+count; n++ {
+		lines = append(lines, n)
+	}
+	return lines
+}
+
+// gitError returns the error err of running the git command cmd, with
+// what git wrote to stderr.
+func gitError(cmd string, err error) error {
+	if e, ok := err.(*exec.ExitError); ok && len(e.Stderr) > 0 {
+		return fmt.Errorf("%s: %s", cmd, strings.TrimSpace(string(e.Stderr)))
+	}
+	return fmt.Errorf("%s: %s", cmd, err)
+}
+
+// A blobReader reads files at given revisions through a git cat-file
+// --batch process.
+type blobReader struct {
+	cmd *exec.Cmd
+	in  io.WriteCloser
+	out *bufio.Reader
+}
+
+func newBlobReader(dir string) (*blobReader, error) {
+	cmd := exec.Command("git", "-C", dir, "cat-file", "--batch")
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &blobReader{cmd, in, bufio.NewReader(out)}, nil
+}
+
+// blob returns the content of the named object, such as a hash or
+// rev:./path, or false if there is no such blob.
+func (b *blobReader) blob(name string) ([]byte, bool, error) {
+	if strings.ContainsAny(name, "\n") {
+		return nil, false, nil // not a name cat-file can be asked for
+	}
+	if _, err := fmt.Fprintf(b.in, "%s\n", name); err != nil {
+		return nil, false, err
+	}
+	h, err := b.out.ReadString('\n')
+	if err != nil {
+		return nil, false, err
+	}
+	// The header is "<object> <type> <size>", or "<name> missing" for a
+	// name that may hold spaces.
+	h = strings.TrimSuffix(h, "\n")
+	if strings.HasSuffix(h, " missing") || strings.HasSuffix(h, " ambiguous") {
+		return nil, false, nil
+	}
+	f := strings.Fields(h)
+	if len(f) != 3 {
+		return nil, false, fmt.Errorf("git cat-file: bad header %q", h)
+	}
+	size, err := strconv.Atoi(f[2])
+	if err != nil {
+		return nil, false, fmt.Errorf("git cat-file: bad header %q", h)
+	}
+	c := make([]byte, size+1)
+	if _, err := io.ReadFull(b.out, c); err != nil {
+		return nil, false, err
+	}
+	return c[:size], f[1] == "blob", nil
+}
+
+func (b *blobReader) close() error {
+	b.in.Close()
+	return b.cmd.Wait()
+}
+
+// A langChurn is how much of a language changed between two revisions:
+// the code lines added and removed, as the lines git diff reports are
+// counted in the files before and after.
+type langChurn struct {
+	Name    string `json:"name"`
+	Files   int    `json:"files"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Net     int    `json:"net"`
+}
+
+type gitDiffReport struct {
+	From      string      `json:"from"`
+	To        string      `json:"to"`
+	Languages []langChurn `json:"languages"`
+	Total     langChurn   `json:"total"`
+	Binary    int         `json:"binary_files"`
+}
+
+// countRevision counts the files of stats at the revision rev, by their
+// old paths if old is set, and returns the statistics of each path
+// counted.
+func countRevision(c *sloc.Counter, b *blobReader, rev string, stats []numstat, old bool) (map[string]sloc.FileStats, error) {
+	c.Reset()
+	for _, s := range stats {
+		p := s.new
+		if old {
+			p = s.old
+		}
+		if s.binary {
+			continue
+		}
+		content, ok, err := b.blob(rev + ":./" + p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if err := c.CountReader(p, bytes.NewReader(content)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	files := map[string]sloc.FileStats{}
+	for _, f := range c.Files() {
+		files[f.Path] = f
+	}
+	return files, nil
+}
+
+// codeLines returns how many of the lines numbered in nums are code in f.
+// Lines whose class f does not know count as code.
+func codeLines(f sloc.FileStats, nums []int) int {
+	n := 0
+	for _, i := range nums {
+		if i > len(f.Lines) || f.Lines[i-1] == sloc.Code {
+			n++
+		}
+	}
+	return n
+}
+
+// gitDiff counts the code lines changed per language between the
+// revisions of rng, given as from..to, in the git work tree dir.
+func gitDiff(c *sloc.Counter, dir, rng string) (*gitDiffReport, error) {
+	i := strings.Index(rng, "..")
+	if i <= 0 || i+2 == len(rng) || strings.Contains(rng, "...") {
+		return nil, fmt.Errorf("the range must be given as REF1..REF2, not %q", rng)
+	}
+	r := &gitDiffReport{From: rng[:i], To: rng[i+2:], Languages: []langChurn{}, Total: langChurn{Name: "Total"}}
+	stats, err := gitNumstat(dir, r.From, r.To)
+	if err != nil {
+		return nil, err
+	}
+	b, err := newBlobReader(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer b.close()
+
+	// Each line is classified within its own file, so every file is
+	// counted, once and under one language.
+	c.KeepFiles, c.KeepLines = true, true
+	c.SplitErlangTests, c.FindDuplicates, c.SkipDuplicates = false, false, false
+	oldFiles, err := countRevision(c, b, r.From, stats, true)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := countRevision(c, b, r.To, stats, false)
+	if err != nil {
+		return nil, err
+	}
+
+	churn := map[string]*langChurn{}
+	for _, s := range stats {
+		if s.binary {
+			r.Binary++
+			continue
+		}
+		of, inOld := oldFiles[s.old]
+		nf, inNew := newFiles[s.new]
+		name := nf.Language
+		if !inNew {
+			if !inOld {
+				continue
+			}
+			name = of.Language
+		}
+		removed, added, err := gitHunks(dir, r.From, r.To, s)
+		if err != nil {
+			return nil, err
+		}
+		if churn[name] == nil {
+			churn[name] = &langChurn{Name: name}
+		}
+		l := churn[name]
+		l.Files++
+		l.Added += codeLines(nf, added)
+		l.Removed += codeLines(of, removed)
+	}
+	for _, l := range churn {
+		l.Net = l.Added - l.Removed
+		r.Languages = append(r.Languages, *l)
+		r.Total.Files += l.Files
+		r.Total.Added += l.Added
+		r.Total.Removed += l.Removed
+		r.Total.Net += l.Net
+	}
+	sort.Sort(byChurn(r.Languages))
+	return r, nil
+}
+
+type byChurn []langChurn
+
+func (d byChurn) Len() int { return len(d) }
+
+func (d byChurn) Less(i, j int) bool {
+	if a, b := d[i].Added+d[i].Removed, d[j].Added+d[j].Removed; a != b {
+		return a > b
+	}
+	return d[i].Name < d[j].Name
+}
+
+func (d byChurn) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
+
+func (r *gitDiffReport) print() {
+	fmt.Printf("Code lines changed from %s to %s:\n", r.From, r.To)
+	w := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Language\tFiles\tAdded\tRemoved\tNet\t")
+	for _, l := range append(r.Languages, r.Total) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%+d\t\n", l.Name, l.Files, l.Added, l.Removed, l.Net)
+	}
+	if r.Binary > 0 {
+		fmt.Fprintf(w, "Binary\t%d\t\t\t\t\n", r.Binary)
+	}
+	w.Flush()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+func TestGitDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("no git")
+	}
+	dir, err := ioutil.TempDir("", "sloc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=a", "-c", "user.email=a@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("dir one/a b.go", "package a\n\n// A.\nvar x = 1\n")
+	write("c.py", "import os\n\n\ndef f():\n    return os.getcwd()\n")
+	write("img.bin", "\x00\x01\x02")
+	git("add", ".")
+	git("commit", "-q", "-m", "one")
+	write("dir one/a b.go", "package a\n\n// A.\n// B.\nvar x = 1\n\nvar y = 2\n")
+	git("mv", "c.py", "new name.py")
+	write("img.bin", "\x00\x01\x03")
+	git("add", ".")
+	git("commit", "-q", "-m", "two")
+
+	r, err := gitDiff(sloc.NewCounter(), dir, "HEAD~1..HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the code lines added or removed count: not "// B.", nor the
+	// blank line, nor the renamed file's unchanged lines.
+	want := []langChurn{
+		{Name: "Go", Files: 1, Added: 1, Net: 1},
+		{Name: "Python", Files: 1},
+	}
+	if !reflect.DeepEqual(r.Languages, want) {
+		t.Errorf("got %+v, want %+v", r.Languages, want)
+	}
+	if r.Total != (langChurn{Name: "Total", Files: 2, Added: 1, Net: 1}) || r.Binary != 1 {
+		t.Errorf("got total %+v and %d binary files", r.Total, r.Binary)
+	}
+
+	if _, err := gitDiff(sloc.NewCounter(), dir, "HEAD~1...HEAD"); err == nil {
+		t.Error("a symmetric difference was taken for a range")
+	}
+}
+
+func TestBlobPathWithSpaces(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("no git")
+	}
+	dir, err := ioutil.TempDir("", "sloc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	b, err := newBlobReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.close()
+	// cat-file answers "<name> missing", where the name has spaces of its
+	// own, rather than "<object> <type> <size>".
+	for _, name := range []string{"HEAD:./a b.go", "HEAD:./a b c d.go", "HEAD:./x"} {
+		if _, ok, err := b.blob(name); ok || err != nil {
+			t.Errorf("%s: got %v, %v; want missing", name, ok, err)
+		}
+	}
+}
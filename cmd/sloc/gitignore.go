@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	return matchSegments(r.segments, strings.Split(rel, "/"))
+}
+
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	r := ignoreRule{}
+	line = strings.TrimRight(line, "\r")
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || line[0] == '#' {
+		return r, false
+	}
+	if line[0] == '!' {
+		r.negate = true
+		line = line[1:]
+	} else if line[0] == '\\' && len(line) > 1 && (line[1] == '#' || line[1] == '!') {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return r, false
+	}
+	r.segments = compileGlob(line)
+	return r, true
+}
+
+// compileGlob splits a glob into its elements. A pattern without a
+// separator matches at any depth.
+func compileGlob(p string) []string {
+	if !strings.Contains(p, "/") {
+		p = "**/" + p
+	}
+	return strings.Split(strings.TrimPrefix(p, "/"), "/")
+}
+
+// matchSegments matches a slash-separated glob against a slash-separated
+// name one element at a time, where a "**" element matches any number of
+// directories.
+func matchSegments(pat, name []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			pat = pat[1:]
+			if len(pat) == 0 {
+				// A trailing "/**" matches everything inside, but not
+				// the directory itself.
+				return len(name) > 0
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pat, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], name[0]); !ok {
+			return false
+		}
+		pat, name = pat[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// A gitignore holds the rules read from one ignore file. Paths handed to
+// the walker are relative to the command-line argument, so dir is the
+// walker's name for the directory and sub is the path from the ignore
+// file's directory down to it.
+type gitignore struct {
+	dir   string
+	sub   string
+	rules []ignoreRule
+}
+
+func (g *gitignore) rel(p string) string {
+	return path.Join(g.sub, relPath(g.dir, p))
+}
+
+func readIgnoreFile(fname, dir, sub string) *gitignore {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	g := &gitignore{dir: path.Clean(dir), sub: sub}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if r, ok := parseIgnoreRule(sc.Text()); ok {
+			g.rules = append(g.rules, r)
+		}
+	}
+	if len(g.rules) == 0 {
+		return nil
+	}
+	return g
+}
+
+type ignoreStack []*gitignore
+
+// Ignored reports whether p is ignored. As in git, later rules take
+// precedence over earlier ones, and deeper files over shallower ones.
+func (s ignoreStack) Ignored(p string, isDir bool) bool {
+	ignored := false
+	for _, g := range s {
+		rel := g.rel(p)
+		for _, r := range g.rules {
+			if r.match(rel, isDir) {
+				ignored = !r.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// push returns the stack extended with the .gitignore in dir, if any.
+func (s ignoreStack) push(dir string) ignoreStack {
+	g := readIgnoreFile(path.Join(dir, ".gitignore"), dir, "")
+	if g == nil {
+		return s
+	}
+	return append(s[:len(s):len(s)], g)
+}
+
+// parentIgnores collects the ignore files that apply to dir from the
+// enclosing repository, from its root down to dir's parent.
+func parentIgnores(dir string) ignoreStack {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	root := abs
+	for {
+		if _, err := os.Stat(filepath.Join(root, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return nil
+		}
+		root = parent
+	}
+
+	var s ignoreStack
+	sub := func(d string) string {
+		r, _ := filepath.Rel(d, abs)
+		return filepath.ToSlash(r)
+	}
+	if g := readIgnoreFile(filepath.Join(root, ".git", "info", "exclude"), dir, sub(root)); g != nil {
+		s = append(s, g)
+	}
+	if root == abs {
+		return s
+	}
+	elems := []string{"."}
+	if rel, _ := filepath.Rel(root, filepath.Dir(abs)); rel != "." {
+		elems = append(elems, strings.Split(rel, string(filepath.Separator))...)
+	}
+	d := root
+	for _, elem := range elems {
+		d = filepath.Join(d, elem)
+		if g := readIgnoreFile(filepath.Join(d, ".gitignore"), dir, sub(d)); g != nil {
+			s = append(s, g)
+		}
+	}
+	return s
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// intervals are the -interval values, each with the start of the interval
+// holding a time.
+var intervals = map[string]func(t time.Time) time.Time{
+	"daily": func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	},
+	"weekly": func(t time.Time) time.Time {
+		d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return d.AddDate(0, 0, -(int(d.Weekday())+6)%7) // back to Monday
+	},
+	"monthly": func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	},
+	"yearly": func(t time.Time) time.Time {
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	},
+}
+
+// nextInterval returns the start of the interval after the one starting at t.
+func nextInterval(interval string, t time.Time) time.Time {
+	switch interval {
+	case "daily":
+		return t.AddDate(0, 0, 1)
+	case "weekly":
+		return t.AddDate(0, 0, 7)
+	case "monthly":
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(1, 0, 0)
+}
+
+// A commit is a commit of the history and when it was made.
+type commit struct {
+	hash string
+	when time.Time
+}
+
+// gitLog returns the commits of the first-parent history of HEAD in dir,
+// oldest first, bounded by since and until when set.
+func gitLog(dir, since, until string) ([]commit, error) {
+	args := []string{"-C", dir, "log", "--first-parent", "--format=%H %ct"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	if until != "" {
+		args = append(args, "--until="+until)
+	}
+	out, err := exec.Command("git", append(args, "HEAD", "--")...).Output()
+	if err != nil {
+		if e, ok := err.(*exec.ExitError); ok && len(e.Stderr) > 0 {
+			return nil, fmt.Errorf("git log: %s", strings.TrimSpace(string(e.Stderr)))
+		}
+		return nil, fmt.Errorf("git log: %s", err)
+	}
+	var commits []commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		f := strings.Fields(line)
+		if len(f) != 2 {
+			continue
+		}
+		sec, err := strconv.ParseInt(f[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		commits = append([]commit{{f[0], time.Unix(sec, 0).UTC()}}, commits...)
+	}
+	return commits, nil
+}
+
+// isShallow reports whether the repository at dir is a shallow clone, and
+// so lacks the history before some commit.
+func isShallow(dir string) bool {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--is-shallow-repository").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// gitTree returns the paths, relative to dir, and objects of the files in
+// the tree of rev. Submodules and symbolic links are left out.
+func gitTree(dir, rev string) (paths, objects []string, err error) {
+	out, err := exec.Command("git", "-C", dir, "ls-tree", "-r", "-z", rev).Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("git ls-tree %s: %s", rev, err)
+	}
+	for _, e := range strings.Split(string(out), "\x00") {
+		// Entries are "<mode> <type> <object>\t<path>".
+		tab := strings.IndexByte(e, '\t')
+		if tab < 0 {
+			continue
+		}
+		f := strings.Fields(e[:tab])
+		if len(f) != 3 || f[1] != "blob" || f[0] == "120000" {
+			continue
+		}
+		paths = append(paths, e[tab+1:])
+		objects = append(objects, f[2])
+	}
+	return paths, objects, nil
+}
+
+// countTree counts the files of the tree of rev in dir that walking the
+// tree checked out would count.
+func (w *walker) countTree(c *sloc.Counter, b *blobReader, dir, rev string) error {
+	paths, objects, err := gitTree(dir, rev)
+	if err != nil {
+		return err
+	}
+	nosloc := map[string]bool{}
+	for _, p := range paths {
+		if path.Base(p) == ".nosloc" {
+			nosloc[path.Dir(p)] = true
+		}
+	}
+	c.Reset()
+	for i, p := range paths {
+		if w.leftOut(p, nosloc) {
+			continue
+		}
+		content, ok, err := b.blob(objects[i])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if maxFileSize > 0 && int64(len(content)) > int64(maxFileSize) {
+			continue
+		}
+		if err := c.CountReader(p, bytes.NewReader(content)); err != nil {
+			return fmt.Errorf("%s at %s: %s", p, rev, err)
+		}
+	}
+	return nil
+}
+
+// A snapshot is the count of an interval of the history, taken at the
+// last commit made by its end.
+type snapshot struct {
+	Date      string            `json:"date"`
+	Commit    string            `json:"commit"`
+	Languages []historyLanguage `json:"languages"`
+}
+
+type historyLanguage struct {
+	Name    string `json:"name"`
+	Files   int    `json:"files"`
+	Code    int    `json:"code"`
+	Comment int    `json:"comment"`
+	Blank   int    `json:"blank"`
+	Total   int    `json:"total"`
+}
+
+// history counts the history of the repository at dir, one snapshot per
+// interval from the first commit within since and until to the last. An
+// interval with no commits of its own repeats the snapshot before it.
+func (w *walker) history(c *sloc.Counter, dir, interval, since, until string) ([]snapshot, error) {
+	start, ok := intervals[interval]
+	if !ok {
+		var names []string
+		for n := range intervals {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("-interval must be one of %s, not %q", strings.Join(names, ", "), interval)
+	}
+	commits, err := gitLog(dir, since, until)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits in the range given")
+	}
+	if isShallow(dir) {
+		fmt.Fprintf(os.Stderr, "  ! %s is a shallow clone, so its history starts at %s\n", dir, commits[0].when.Format("2006-01-02"))
+	}
+	last := map[time.Time]commit{} // the last commit of each interval
+	for _, cm := range commits {
+		last[start(cm.when)] = cm
+	}
+
+	b, err := newBlobReader(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer b.close()
+
+	series := []snapshot{}
+	var prev snapshot
+	end := start(commits[len(commits)-1].when)
+	for t := start(commits[0].when); !t.After(end); t = nextInterval(interval, t) {
+		cm, ok := last[t]
+		if ok {
+			if err := w.countTree(c, b, dir, cm.hash); err != nil {
+				return nil, err
+			}
+			d, _ := languageResults(c.Results())
+			prev = snapshot{Commit: cm.hash, Languages: []historyLanguage{}}
+			for _, l := range d {
+				prev.Languages = append(prev.Languages, historyLanguage{l.Name, l.FileCount, l.CodeLines, l.CommentLines, l.BlankLines, l.TotalLines})
+			}
+		}
+		prev.Date = t.Format("2006-01-02")
+		series = append(series, prev)
+	}
+	return series, nil
+}
+
+func printHistoryCSV(series []snapshot) {
+	w := csv.NewWriter(os.Stdout)
+	itoa := strconv.Itoa
+	w.Write([]string{"date", "commit", "language", "files", "code", "comment", "blank", "total"})
+	for _, s := range series {
+		for _, l := range s.Languages {
+			w.Write([]string{s.Date, s.Commit, l.Name, itoa(l.Files), itoa(l.Code), itoa(l.Comment), itoa(l.Blank), itoa(l.Total)})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// An htmlDir is the per-directory breakdown shown in -by-file mode.
+type htmlDir struct {
+	Name  string
+	Stats LResult
+	Files []sloc.FileStats
+}
+
+type htmlReport struct {
+	Version   string
+	Paths     []string
+	Languages LData
+	Total     LResult
+	Dirs      []htmlDir
+}
+
+// Width returns the length of the bar for r, relative to the language
+// with the most code.
+func (h *htmlReport) Width(r LResult) float64 {
+	max := 0
+	for _, l := range h.Languages {
+		if l.CodeLines > max {
+			max = l.CodeLines
+		}
+	}
+	return percent(r.CodeLines, max)
+}
+
+func (h *htmlReport) Percent(r LResult) float64 {
+	return percent(r.CodeLines, h.Total.CodeLines)
+}
+
+// directories groups files by the directory they are in, in path order.
+func directories(files []sloc.FileStats) []htmlDir {
+	idx := map[string]int{}
+	var dirs []htmlDir
+	for _, f := range files {
+		name := filepath.ToSlash(filepath.Dir(f.Path))
+		i, ok := idx[name]
+		if !ok {
+			i = len(dirs)
+			idx[name] = i
+			dirs = append(dirs, htmlDir{Name: name})
+		}
+		d := &dirs[i]
+		d.Files = append(d.Files, f)
+		d.Stats.Add(LResult{name, f.FileCount, f.CodeLines, f.CommentLines, f.BlankLines, f.TotalLines, f.DocLines, f.Tags, f.MaxLineLength, f.TotalLineLength})
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Name < dirs[j].Name })
+	return dirs
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>sloc {{.Version}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { padding: 0.2em 0.8em; border-bottom: 1px solid #ddd; }
+th { text-align: left; }
+td.n { text-align: right; font-variant-numeric: tabular-nums; }
+tr.total td { font-weight: bold; }
+td.bar { width: 20em; }
+td.bar div { background: #4a7bd0; height: 0.9em; }
+details { margin: 0.3em 0; }
+summary { cursor: pointer; }
+footer { color: #777; font-size: smaller; }
+</style>
+</head>
+<body>
+<h1>Lines of code</h1>
+<p>Paths:{{range .Paths}} <code>{{.}}</code>{{end}}</p>
+{{if .Languages}}<table>
+<tr><th>Language</th><th>Files</th><th>Code</th><th>Comment</th><th>Blank</th><th>Total</th><th>Code %</th><th></th></tr>
+{{range .Languages}}<tr><td>{{.Name}}</td><td class="n">{{.FileCount}}</td><td class="n">{{.CodeLines}}</td><td class="n">{{.CommentLines}}</td><td class="n">{{.BlankLines}}</td><td class="n">{{.TotalLines}}</td><td class="n">{{printf "%.1f" ($.Percent .)}}</td><td class="bar"><div style="width: {{printf "%.1f" ($.Width .)}}%"></div></td></tr>
+{{end}}<tr class="total"><td>Total</td><td class="n">{{.Total.FileCount}}</td><td class="n">{{.Total.CodeLines}}</td><td class="n">{{.Total.CommentLines}}</td><td class="n">{{.Total.BlankLines}}</td><td class="n">{{.Total.TotalLines}}</td><td></td><td></td></tr>
+</table>
+{{end}}{{if .Dirs}}<h2>Directories</h2>
+{{range .Dirs}}<details>
+<summary><code>{{.Name}}</code>: {{.Stats.FileCount}} files, {{.Stats.CodeLines}} code, {{.Stats.CommentLines}} comment, {{.Stats.BlankLines}} blank</summary>
+<table>
+<tr><th>File</th><th>Language</th><th>Code</th><th>Comment</th><th>Blank</th><th>Total</th></tr>
+{{range .Files}}<tr><td><code>{{.Path}}</code></td><td>{{.Language}}</td><td class="n">{{.CodeLines}}</td><td class="n">{{.CommentLines}}</td><td class="n">{{.BlankLines}}</td><td class="n">{{.TotalLines}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}{{end}}<footer>Generated by sloc {{.Version}}</footer>
+</body>
+</html>
+`))
+
+func newHTMLReport(c *sloc.Counter, paths []string) *htmlReport {
+	r := &htmlReport{Version: VERSION, Paths: paths}
+	r.Languages, r.Total = languageResults(c.Results())
+	if *byFile {
+		r.Dirs = directories(c.Files())
+	}
+	return r
+}
+
+func (h *htmlReport) write(w io.Writer) error {
+	return htmlTemplate.Execute(w, h)
+}
+
+// writeHTML renders a self-contained report to the file fname.
+func writeHTML(c *sloc.Counter, paths []string, fname string) error {
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	if err := newHTMLReport(c, paths).write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// htmlFiles are counted for the reports, with names and content needing
+// escaping.
+var htmlFiles = []struct{ name, content string }{
+	{"main.go", "package main\n\n// main runs.\nfunc main() {}\n"},
+	{"cmd/tool/tool.go", "package tool\n\nvar x = `<b>`\n"},
+	{"web/<app>.js", "/* a & b */\nlet s = \"<script>\";\n"},
+	{"scripts/build.py", "# build\nprint('hi')\n\n\n"},
+}
+
+func TestHTMLReport(t *testing.T) {
+	for _, tt := range []struct {
+		golden string
+		byFile bool
+	}{
+		{"report.golden", false},
+		{"report_by_file.golden", true},
+	} {
+		saved := *byFile
+		*byFile = tt.byFile
+		c := sloc.NewCounter()
+		c.KeepFiles = tt.byFile
+		for _, f := range htmlFiles {
+			if err := c.CountReader(f.name, strings.NewReader(f.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r := newHTMLReport(c, []string{".", "<other>"})
+		*byFile = saved
+		r.Version = "test" // so that releases leave the golden files be
+
+		var b bytes.Buffer
+		if err := r.write(&b); err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", tt.golden)
+		if *update {
+			if err := ioutil.WriteFile(golden, b.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("%s; run go test -update to create it", err)
+		}
+		if !bytes.Equal(b.Bytes(), want) {
+			t.Errorf("%s: the report differs from the golden file; got:\n%s", tt.golden, b.Bytes())
+		}
+	}
+}
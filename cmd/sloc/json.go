@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// A jsonReport is the document written by -json. It is a struct rather
+// than a map so that the order of its keys is stable.
+type jsonReport struct {
+	Version   string            `json:"version"`
+	Timestamp string            `json:"timestamp"`
+	Paths     []string          `json:"paths"`
+	Filters   *jsonFilters      `json:"filters,omitempty"`
+	Languages []jsonLanguage    `json:"languages,omitempty"`
+	Total     *jsonLanguage     `json:"total,omitempty"`
+	Files     []jsonFileStats   `json:"files,omitempty"`
+	Binary    int               `json:"skipped_binary"`
+	Minified  int               `json:"skipped_minified,omitempty"`
+	Unrec     *jsonUnrecognized `json:"unrecognized"`
+	Cocomo    *cocomoEstimate   `json:"cocomo,omitempty"`
+	Diff      *diffReport       `json:"diff,omitempty"`
+
+	Duplicates   []jsonDuplicate `json:"duplicates,omitempty"`
+	Deduplicated int             `json:"deduplicated_lines,omitempty"`
+}
+
+// jsonFilters records the language filters active in a run.
+type jsonFilters struct {
+	Langs        []string `json:"langs,omitempty"`
+	ExcludeLangs []string `json:"exclude_langs,omitempty"`
+}
+
+type jsonLanguage struct {
+	Name        string  `json:"name"`
+	Files       int     `json:"files"`
+	Code        int     `json:"code"`
+	Comment     int     `json:"comment"`
+	Doc         int     `json:"doc"`
+	Blank       int     `json:"blank"`
+	Total       int     `json:"total"`
+	CodePercent float64 `json:"code_percent"`
+
+	// CommentRatio is the percentage of the code and comment lines that
+	// are comments; BlankRatio that of all lines that are blank.
+	CommentRatio float64 `json:"comment_ratio"`
+	BlankRatio   float64 `json:"blank_ratio"`
+
+	MaxLineLength int     `json:"max_line_length"`
+	AvgLineLength float64 `json:"avg_line_length"`
+
+	Tags map[string]int `json:"tags,omitempty"`
+}
+
+type jsonFileStats struct {
+	Path     string `json:"path"`
+	Language string `json:"language"`
+	Code     int    `json:"code"`
+	Comment  int    `json:"comment"`
+	Doc      int    `json:"doc"`
+	Blank    int    `json:"blank"`
+	Total    int    `json:"total"`
+
+	MaxLineLength int     `json:"max_line_length"`
+	AvgLineLength float64 `json:"avg_line_length"`
+
+	Tags map[string]int `json:"tags,omitempty"`
+}
+
+// percent returns n as a percentage of total, rounded to one decimal.
+func percent(n, total int) float64 {
+	return float64(permille(n, total)) / 10
+}
+
+func newJSONLanguage(r LResult, totalCode int) jsonLanguage {
+	return jsonLanguage{r.Name, r.FileCount, r.CodeLines, r.CommentLines, r.DocLines, r.BlankLines, r.TotalLines, percent(r.CodeLines, totalCode),
+		percent(r.CommentLines, r.CodeLines+r.CommentLines), percent(r.BlankLines, r.TotalLines),
+		r.MaxLineLength, math.Round(r.averageLineLength()*10) / 10, r.Tags}
+}
+
+func newJSONReport(c *sloc.Counter, paths []string) *jsonReport {
+	r := &jsonReport{
+		Version:   VERSION,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Paths:     paths,
+		Binary:    c.SkippedBinary(),
+		Minified:  c.SkippedMinified(),
+		Unrec:     newJSONUnrecognized(c.Unrecognized()),
+	}
+	if *onlyLangs != "" || *excludeLangs != "" {
+		r.Filters = &jsonFilters{splitList(*onlyLangs), splitList(*excludeLangs)}
+	}
+	if *duplicates || *skipDups {
+		dups := c.Duplicates()
+		r.Duplicates = newJSONDuplicates(dups)
+		if *skipDups {
+			r.Deduplicated = deduplicatedLines(dups)
+		}
+	}
+	d, total := languageResults(c.Results())
+	if *cocomo {
+		e := estimateCocomo(total.CodeLines, *cocomoModel, *cocomoMode, *cocomoSalary, *cocomoOver, *cocomoEAF)
+		r.Cocomo = &e
+	}
+	if *byFile {
+		for _, f := range c.Files() {
+			r.Files = append(r.Files, jsonFileStats{f.Path, f.Language, f.CodeLines, f.CommentLines, f.DocLines, f.BlankLines, f.TotalLines,
+				f.MaxLineLength, math.Round(f.AverageLineLength()*10) / 10, f.Tags})
+		}
+		if *noSummary {
+			return r
+		}
+	}
+	r.Languages = []jsonLanguage{}
+	for _, i := range d {
+		r.Languages = append(r.Languages, newJSONLanguage(i, total.CodeLines))
+	}
+	t := newJSONLanguage(total, total.CodeLines)
+	r.Total = &t
+	return r
+}
+
+// compatStats are the statistics as -json gave them before the
+// structured report, frozen whatever sloc.Stats has gained since.
+type compatStats struct {
+	FileCount    int
+	TotalLines   int
+	CodeLines    int
+	BlankLines   int
+	CommentLines int
+}
+
+type compatFile struct {
+	Path     string
+	Language string
+	compatStats
+}
+
+func newCompatStats(s sloc.Stats) compatStats {
+	return compatStats{s.FileCount, s.TotalLines, s.CodeLines, s.BlankLines, s.CommentLines}
+}
+
+func compatLanguages(c *sloc.Counter) map[string]compatStats {
+	m := map[string]compatStats{}
+	for n, s := range c.Results() {
+		m[n] = newCompatStats(s)
+	}
+	return m
+}
+
+// compatJSON is the shape -json produced before the structured report:
+// the statistics keyed by language, or those alongside the files with
+// -by-file.
+func compatJSON(c *sloc.Counter) interface{} {
+	var v interface{} = compatLanguages(c)
+	if *byFile {
+		o := struct {
+			Files     []compatFile
+			Languages map[string]compatStats `json:",omitempty"`
+		}{}
+		for _, f := range c.Files() {
+			o.Files = append(o.Files, compatFile{f.Path, f.Language, newCompatStats(f.Stats)})
+		}
+		if !*noSummary {
+			o.Languages = compatLanguages(c)
+		}
+		v = o
+	}
+	return v
+}
+
+func printJSON(c *sloc.Counter, paths []string, diff *diffReport) {
+	var v interface{}
+	if *jsonCompat {
+		v = compatJSON(c)
+	} else {
+		r := newJSONReport(c, paths)
+		r.Diff = diff
+		v = r
+	}
+	printJSONValue(v)
+}
+
+func printJSONValue(v interface{}) {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(bs))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+func TestCompatJSON(t *testing.T) {
+	savedByFile, savedNoSummary := *byFile, *noSummary
+	defer func() { *byFile, *noSummary = savedByFile, savedNoSummary }()
+
+	c := sloc.NewCounter()
+	c.KeepFiles = true
+	files := map[string]string{
+		"a.go": "// Package a does it.\npackage a\n\n// TODO: more.\nvar x = \"" + strings.Repeat("x", 100) + "\"\n",
+		"b.py": "\"\"\"B.\"\"\"\nx = 1\n",
+	}
+	for _, n := range []string{"a.go", "b.py"} {
+		if err := c.CountReader(n, strings.NewReader(files[n])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the five original statistics, under their original names,
+	// whatever sloc.Stats has gained since.
+	langs := `{"Go":{"FileCount":1,"TotalLines":5,"CodeLines":2,"BlankLines":1,"CommentLines":2},` +
+		`"Python":{"FileCount":1,"TotalLines":2,"CodeLines":1,"BlankLines":0,"CommentLines":1}}`
+	goFile := `{"Path":"a.go","Language":"Go","FileCount":1,"TotalLines":5,"CodeLines":2,"BlankLines":1,"CommentLines":2}`
+	pyFile := `{"Path":"b.py","Language":"Python","FileCount":1,"TotalLines":2,"CodeLines":1,"BlankLines":0,"CommentLines":1}`
+	tests := []struct {
+		byFile, noSummary bool
+		want              string
+	}{
+		{false, false, langs},
+		{true, false, `{"Files":[` + goFile + `,` + pyFile + `],"Languages":` + langs + `}`},
+		{true, true, `{"Files":[` + goFile + `,` + pyFile + `]}`},
+	}
+	for _, tt := range tests {
+		*byFile, *noSummary = tt.byFile, tt.noSummary
+		bs, err := json.Marshal(compatJSON(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(bs) != tt.want {
+			t.Errorf("-by-file %v, -no-summary %v:\ngot  %s\nwant %s", tt.byFile, tt.noSummary, bs, tt.want)
+		}
+	}
+}
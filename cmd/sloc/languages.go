@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// A jsonLangInfo describes a language for -list-languages -json.
+type jsonLangInfo struct {
+	Name         string      `json:"name"`
+	Extensions   []string    `json:"extensions,omitempty"`
+	Filenames    []string    `json:"filenames,omitempty"`
+	Prefixes     []string    `json:"prefixes,omitempty"`
+	Suffixes     []string    `json:"suffixes,omitempty"`
+	Paths        []string    `json:"paths,omitempty"`
+	LineComments []string    `json:"line_comments,omitempty"`
+	Blocks       []jsonBlock `json:"block_comments,omitempty"`
+}
+
+type jsonBlock struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Nesting bool   `json:"nesting,omitempty"`
+}
+
+// patterns describes the files m matches.
+func patterns(m sloc.Matcher) string {
+	var s []string
+	s = append(s, m.Exts...)
+	s = append(s, m.Names...)
+	for _, prefix := range m.Prefixes {
+		s = append(s, prefix+"*")
+	}
+	for _, suffix := range m.Suffixes {
+		s = append(s, "*"+suffix)
+	}
+	s = append(s, m.Paths...)
+	return strings.Join(s, " ")
+}
+
+// lineComments returns all the line comment markers of l, including
+// those that only count at the start of a line.
+func lineComments(l sloc.Language) []string {
+	return append(append([]string(nil), l.LineComments...), l.LeadComments...)
+}
+
+// blockComments returns all the block comment markers of l, including
+// those that only start at the start of a line.
+func blockComments(l sloc.Language) []sloc.Block {
+	return append(append([]sloc.Block(nil), l.Blocks...), l.LeadBlocks...)
+}
+
+// blocks describes block comment markers such as "/* */" and, for those
+// that nest, "(* *) nested".
+func blocks(bs []sloc.Block) string {
+	var s []string
+	for _, b := range bs {
+		d := b.Start + " " + b.End
+		if b.Nesting {
+			d += " nested"
+		}
+		s = append(s, d)
+	}
+	return strings.Join(s, ", ")
+}
+
+func printLanguages(langs []sloc.Language) {
+	if *useJson {
+		var v []jsonLangInfo
+		for _, l := range langs {
+			i := jsonLangInfo{l.Name(), l.Exts, l.Names, l.Prefixes, l.Suffixes, l.Paths, lineComments(l), nil}
+			for _, b := range blockComments(l) {
+				i.Blocks = append(i.Blocks, jsonBlock{b.Start, b.End, b.Nesting})
+			}
+			v = append(v, i)
+		}
+		bs, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(bs))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Language\tFiles\tLine comments\tBlock comments\t")
+	for _, l := range langs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", l.Name(), patterns(l.Matcher), strings.Join(lineComments(l), " "), blocks(blockComments(l)))
+	}
+	w.Flush()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// A langLimits is a flag.Value collecting per-language limits given as
+// Name=N, either as repeated flags or as comma-separated lists.
+type langLimits map[string]int
+
+func (l langLimits) String() string {
+	var s []string
+	for name, n := range l {
+		s = append(s, fmt.Sprintf("%s=%d", name, n))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+func (l langLimits) Set(v string) error {
+	for _, kv := range strings.Split(v, ",") {
+		if kv = strings.TrimSpace(kv); kv == "" {
+			continue
+		}
+		i := strings.LastIndex(kv, "=")
+		if i < 0 {
+			return fmt.Errorf("%q is not of the form Language=N", kv)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(kv[i+1:]))
+		if err != nil || n < 0 {
+			return fmt.Errorf("%q: invalid limit", kv)
+		}
+		l[strings.TrimSpace(kv[:i])] = n
+	}
+	return nil
+}
+
+var maxLangCode = langLimits{}
+
+// languageName returns the name of the language, as results name it, that
+// name gives in any case: one of c's or one that counting derives from them.
+func languageName(c *sloc.Counter, name string) (string, bool) {
+	if l, ok := c.Lookup(name); ok {
+		return l.Name(), true
+	}
+	for _, n := range []string{sloc.ErlangTest, sloc.MinifiedJS} {
+		if strings.EqualFold(n, name) {
+			return n, true
+		}
+	}
+	lower := strings.ToLower(name)
+	if base := strings.TrimSuffix(lower, " (generated)"); base != lower {
+		if n, ok := languageName(c, name[:len(base)]); ok {
+			return n + " (generated)", true
+		}
+	}
+	if strings.HasPrefix(lower, "jupyter (") && strings.HasSuffix(lower, ")") {
+		if n, ok := languageName(c, name[len("jupyter ("):len(name)-1]); ok {
+			return "Jupyter (" + n + ")", true
+		}
+	}
+	return "", false
+}
+
+// resolveLimits renames the languages of -max-code-lines-per-lang as c
+// names them, and fails for those it does not know, lest a typo turn a
+// limit off.
+func resolveLimits(c *sloc.Counter) error {
+	limits := langLimits{}
+	for name, n := range maxLangCode {
+		canon, ok := languageName(c, name)
+		if !ok {
+			return fmt.Errorf("-max-code-lines-per-lang: unknown language %q", name)
+		}
+		limits[canon] = n
+	}
+	maxLangCode = limits
+	return nil
+}
+
+// checkLimits returns a message for each limit the results exceed. A
+// limit of zero is no limit, for a language as for the totals.
+func checkLimits(info map[string]sloc.Stats) []string {
+	var v []string
+	_, total := languageResults(info)
+	if *maxCode > 0 && total.CodeLines > *maxCode {
+		v = append(v, fmt.Sprintf("-max-code-lines %d exceeded: %d code lines", *maxCode, total.CodeLines))
+	}
+	if *maxTotal > 0 && total.TotalLines > *maxTotal {
+		v = append(v, fmt.Sprintf("-max-total-lines %d exceeded: %d lines", *maxTotal, total.TotalLines))
+	}
+	var names []string
+	for name := range maxLangCode {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if n := info[name].CodeLines; maxLangCode[name] > 0 && n > maxLangCode[name] {
+			v = append(v, fmt.Sprintf("-max-code-lines-per-lang %s=%d exceeded: %d code lines", name, maxLangCode[name], n))
+		}
+	}
+	return v
+}
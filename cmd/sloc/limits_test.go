@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+func TestResolveLimits(t *testing.T) {
+	saved := maxLangCode
+	defer func() { maxLangCode = saved }()
+
+	maxLangCode = langLimits{}
+	if err := maxLangCode.Set("go=10, PYTHON=20,c++=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := resolveLimits(sloc.NewCounter()); err != nil {
+		t.Fatal(err)
+	}
+	if want := (langLimits{"Go": 10, "Python": 20, "C++": 0}); !reflect.DeepEqual(maxLangCode, want) {
+		t.Errorf("got %v, want %v", maxLangCode, want)
+	}
+
+	// The languages that counting derives from those of the table.
+	maxLangCode = langLimits{}
+	if err := maxLangCode.Set("javascript (minified)=1,go (GENERATED)=2,erlangtest=3,jupyter (python)=4"); err != nil {
+		t.Fatal(err)
+	}
+	if err := resolveLimits(sloc.NewCounter()); err != nil {
+		t.Fatal(err)
+	}
+	want := langLimits{sloc.MinifiedJS: 1, "Go (generated)": 2, sloc.ErlangTest: 3, "Jupyter (Python)": 4}
+	if !reflect.DeepEqual(maxLangCode, want) {
+		t.Errorf("got %v, want %v", maxLangCode, want)
+	}
+
+	maxLangCode = langLimits{"Golang": 10}
+	if err := resolveLimits(sloc.NewCounter()); err == nil {
+		t.Error("an unknown language resolved")
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	savedCode, savedTotal, savedLang := *maxCode, *maxTotal, maxLangCode
+	defer func() { *maxCode, *maxTotal, maxLangCode = savedCode, savedTotal, savedLang }()
+
+	info := map[string]sloc.Stats{
+		"Go":     {FileCount: 2, CodeLines: 30, TotalLines: 40},
+		"Python": {FileCount: 1, CodeLines: 5, TotalLines: 10},
+	}
+	tests := []struct {
+		name           string
+		maxCode, total int
+		langs          langLimits
+		violations     int
+	}{
+		{"no limits", 0, 0, langLimits{}, 0},
+		{"zero is no limit", 0, 0, langLimits{"Go": 0, "Python": 0}, 0},
+		{"under", 35, 50, langLimits{"Go": 30}, 0},
+		{"code over", 34, 0, langLimits{}, 1},
+		{"total over", 0, 49, langLimits{}, 1},
+		{"language over", 0, 0, langLimits{"Go": 29, "Python": 5}, 1},
+		{"all over", 1, 1, langLimits{"Go": 1, "Python": 1}, 4},
+	}
+	for _, tt := range tests {
+		*maxCode, *maxTotal, maxLangCode = tt.maxCode, tt.total, tt.langs
+		if v := checkLimits(info); len(v) != tt.violations {
+			t.Errorf("%s: got %q, want %d violations", tt.name, v, tt.violations)
+		}
+	}
+}
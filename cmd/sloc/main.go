@@ -0,0 +1,616 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+const VERSION = `0.4`
+
+// count counts files using the given number of workers.
+func count(c *sloc.Counter, files []string, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	ch := make(chan string, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range ch {
+				if err := countFile(c, f); err != nil {
+					fmt.Fprintf(os.Stderr, "  ! %s\n", err.Error())
+					fmt.Fprintf(os.Stderr, "  ! %s\n", f)
+				}
+			}
+		}()
+	}
+	for _, f := range files {
+		ch <- f
+	}
+	close(ch)
+	wg.Wait()
+}
+
+var (
+	cpuprofile   = flag.String("cpuprofile", "", "write cpu profile to file")
+	useJson      = flag.Bool("json", false, "JSON-format output")
+	jsonCompat   = flag.Bool("json-compat", false, "JSON-format output in the shape used before version 0.4")
+	useCSV       = flag.Bool("csv", false, "CSV-format output")
+	useMD        = flag.Bool("markdown", false, "Markdown-format output")
+	htmlOut      = flag.String("html", "", "also write an HTML report to this file")
+	filesFrom    = flag.String("files-from", "", "count the files listed in this file (- for stdin) instead of walking directories")
+	nulSep       = flag.Bool("0", false, "-files-from lists paths separated by NUL rather than newline")
+	showUnrec    = flag.Bool("show-unrecognized", false, "list the files that matched no language")
+	countBinary  = flag.Bool("count-binary", false, "count files that look binary instead of skipping them")
+	useStdin     = flag.Bool("stdin", false, "count the content of stdin instead of files")
+	stdinLang    = flag.String("lang", "", "language of the content counted with -stdin")
+	stdinName    = flag.String("stdin-name", "", "file name to infer the language of -stdin content from")
+	saveFile     = flag.String("save", "", "save the results as a baseline to this file")
+	diffFile     = flag.String("diff", "", "report the changes since the baseline in this file")
+	version      = flag.Bool("V", false, "display version info and exit")
+	configFile   = flag.String("config", "", "read language definitions from this file rather than .sloc.json or .sloc.toml")
+	onlyLangs    = flag.String("langs", "", "count only these comma-separated languages")
+	excludeLangs = flag.String("exclude-langs", "", "do not count these comma-separated languages")
+	forceLang    = flag.String("force-lang", "", "count files that match no language as this one")
+	listLangs    = flag.Bool("list-languages", false, "list the languages recognized and exit")
+	sortBy       = flag.String("sort", "code", "sort languages by name or by a column such as code, comment-ratio or max-length")
+	reverse      = flag.Bool("reverse", false, "reverse the sort order")
+	columnList   = flag.String("columns", "files,code,comment,blank,total", "comma-separated columns to show in tables")
+	duplicates   = flag.Bool("duplicates", false, "list the groups of files with the same content")
+	skipDups     = flag.Bool("skip-duplicates", false, "count the files with the same content only once")
+	todo         = flag.Bool("todo", false, "count the -todo-tags found in comments, and list where with -verbose")
+	todoTags     = flag.String("todo-tags", "TODO,FIXME,HACK,XXX,BUG", "comma-separated tags counted with -todo")
+	lineLengths  = flag.Bool("line-lengths", false, "add columns of the longest and the average line length, in characters")
+	ratios       = flag.Bool("ratios", false, "add columns of comment density and the share of blank lines")
+	docColumn    = flag.Bool("doc-comments", false, "add a column of the comment lines that are documentation, such as Javadoc and docstrings")
+	byFile       = flag.Bool("by-file", false, "report statistics for each file")
+	noSummary    = flag.Bool("no-summary", false, "omit the per-language summary in -by-file mode")
+	gitRange     = flag.String("git-diff", "", "count the lines changed per language between two git revisions, as in v1.2..v1.3")
+	history      = flag.Bool("history", false, "count each -interval of the git history of HEAD, as CSV or with -json as JSON")
+	interval     = flag.String("interval", "monthly", "with -history, count one commit per day, week, month or year: daily, weekly, monthly or yearly")
+	since        = flag.String("since", "", "with -history, start at this date, as git log takes it")
+	until        = flag.String("until", "", "with -history, stop at this date, as git log takes it")
+	noArchives   = flag.Bool("no-archives", false, "count .zip, .jar, .whl and .tar(.gz) files as files rather than their entries")
+	remoteURL    = flag.String("remote", "", "fetch the git repository at this URL into a temporary directory and count it")
+	remoteRef    = flag.String("ref", "", "with -remote or a git URL, count this branch, tag or commit rather than the default branch")
+	useGit       = flag.Bool("git", false, "count the files git tracks instead of walking directories")
+	gitSubs      = flag.Bool("git-submodules", false, "with -git, count the files of submodules too")
+	noGitignore  = flag.Bool("no-gitignore", false, "count files matched by .gitignore")
+	followLinks  = flag.Bool("follow-symlinks", false, "follow symbolic links while walking directories")
+	noDefIgnores = flag.Bool("no-default-ignores", false, "count vendor, node_modules and other dependency and build directories")
+	verbose      = flag.Bool("verbose", false, "report progress details on stderr")
+	quiet        = flag.Bool("quiet", false, "do not print notes on stderr")
+	workers      = flag.Int("j", runtime.NumCPU(), "number of files to count in parallel")
+	foldJSX      = flag.Bool("fold-jsx", false, "count JSX and TSX as JavaScript and TypeScript")
+	buildFiles   = flag.Bool("build-as-starlark", true, "count files named just BUILD as Starlark, as Bazel does")
+	incPascal    = flag.Bool("inc-as-pascal", false, "count .inc files as Pascal include files")
+	group        = flag.String("group", "", "count the languages of a category together: config or docs")
+	foldNotebook = flag.Bool("fold-notebooks", false, "count Jupyter notebooks as the language of their kernel")
+	rawCells     = flag.Bool("raw-cells-as-blank", false, "count the raw cells of Jupyter notebooks as blank lines")
+	splitTests   = flag.Bool("split-tests", true, "report Go tests separately from the rest of Go")
+	erlangTests  = flag.Bool("split-erlang-tests", false, "report the -ifdef(TEST) sections of Erlang files as ErlangTest")
+	minifiedLen  = flag.Int("minified-line-length", 200, "report JavaScript with longer lines on average, or named *.min.js, as minified (0 to not)")
+	skipMinified = flag.Bool("skip-minified", false, "skip minified JavaScript instead of reporting it")
+	generated    = flag.Bool("include-generated", false, "count generated files, such as *.pb.go, with the hand-written ones")
+	mixed        = flag.String("mixed", "code", "count lines with both code and a comment as code, comment or both")
+	dataComments = flag.Bool("data-as-comments", false, "count Perl __END__ and __DATA__ sections as comments")
+	docsComments = flag.Bool("docs-as-comments", true, "count Elixir @moduledoc and @doc heredocs as comments")
+	pragmasCode  = flag.Bool("pragmas-as-code", false, "count Haskell {-# ... #-} pragmas as code")
+	discarded    = flag.Bool("discarded-as-comments", false, "count Clojure #_ and (comment ...) forms and Scheme #; datums as comments")
+	mLang        = flag.String("m-lang", "auto", "language of .m files: objc, matlab, mathematica or auto")
+	plLang       = flag.String("pl-lang", "auto", "language of .pl files: perl, prolog, raku or auto")
+	maxCode      = flag.Int("max-code-lines", 0, "fail if there are more code lines than this; 0 is no limit")
+	maxTotal     = flag.Int("max-total-lines", 0, "fail if there are more lines than this; 0 is no limit")
+	cocomo       = flag.Bool("cocomo", false, "estimate development effort and cost with COCOMO")
+	cocomoSalary = flag.Float64("cocomo-salary", 56286, "average yearly developer salary for -cocomo")
+	cocomoOver   = flag.Float64("cocomo-overhead", 2.4, "multiplier applied to salaries for -cocomo")
+	cocomoEAF    = flag.Float64("cocomo-eaf", 1.0, "effort adjustment factor for -cocomo")
+	cocomoModel  = flag.String("cocomo-model", "basic", "COCOMO model for -cocomo: basic or intermediate")
+	cocomoMode   = flag.String("cocomo-mode", "organic", "development mode for -cocomo: organic, semi-detached or embedded")
+)
+
+func init() {
+	flag.BoolVar(verbose, "v", false, "shorthand for -verbose")
+	flag.Var(&excludes, "exclude", "skip paths matching these glob patterns (repeatable, comma-separated)")
+	flag.Var(&includes, "include", "only count files matching these glob patterns (repeatable, comma-separated)")
+	flag.Var(&maxFileSize, "max-file-size", "skip files larger than this many bytes (K, M and G suffixes allowed)")
+	flag.Var(extMappings, "ext", "count files with these extensions as the given languages, as in inc=PHP,tpl=HTML (repeatable)")
+	flag.Var(maxLangCode, "max-code-lines-per-lang", "fail if a language has more code lines than given as Language=N, in any case (repeatable, comma-separated); 0 is no limit")
+}
+
+// mLangs maps the values of -m-lang to languages.
+var mLangs = map[string]string{"objc": "Objective-C", "matlab": "MATLAB", "mathematica": "Mathematica", "auto": ""}
+
+// plLangs maps the values of -pl-lang to languages.
+var plLangs = map[string]string{"perl": "Perl", "prolog": "Prolog", "raku": "Raku", "auto": ""}
+
+var mixedPolicies = map[string]sloc.Mixed{"code": sloc.MixedCode, "comment": sloc.MixedComment, "both": sloc.MixedBoth}
+
+// newCounter returns a Counter set up as the flags say.
+func newCounter() *sloc.Counter {
+	c := sloc.NewCounter()
+	c.KeepFiles = *byFile || (*todo && *verbose)
+	c.CountBinary = *countBinary
+	c.Options.DataAsComments = *dataComments
+	c.Options.RawCellsAsBlank = *rawCells
+	c.Options.DocsAsCode = !*docsComments
+	c.Options.PragmasAsCode = *pragmasCode
+	c.Options.DiscardedAsComments = *discarded
+	c.FoldNotebooks = *foldNotebook
+	c.SplitErlangTests = *erlangTests
+	c.MinifiedLineLength = *minifiedLen
+	c.SkipMinified = *skipMinified
+	c.SplitGenerated = !*generated
+	c.FindDuplicates = *duplicates
+	c.SkipDuplicates = *skipDups
+	c.Options.Mixed = mixedPolicies[*mixed]
+	if *todo {
+		c.Options.Tags = splitList(*todoTags)
+	}
+	folds := map[string]string{}
+	if *foldJSX {
+		folds["JSX"], folds["TSX"] = "JavaScript", "TypeScript"
+	}
+	if !*splitTests {
+		folds["GoTest"] = "Go"
+	}
+	c.Languages = sloc.Rename(c.Languages, folds)
+	if !*buildFiles {
+		c.Languages = withoutName(c.Languages, "BUILD")
+	}
+	if *incPascal {
+		mapExtensions(c, map[string]string{"inc": "Pascal"}) // Pascal is always there
+	}
+	if name := mLangs[*mLang]; name != "" {
+		c.Detectors[".m"] = sloc.DetectAs(name)
+	}
+	if name := plLangs[*plLang]; name != "" {
+		c.Detectors[".pl"] = sloc.DetectAs(name)
+	}
+	if *verbose {
+		c.Log = func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, format, args...)
+		}
+	}
+	return c
+}
+
+// groupLanguages renames the languages of the category given by -group to
+// the category, and has the detectors follow. It comes after -ext and the
+// configuration files, which name languages as they are.
+func groupLanguages(c *sloc.Counter) {
+	folds := map[string]string{}
+	for name, cat := range sloc.Categories {
+		if strings.EqualFold(cat, *group) {
+			folds[name] = cat
+		}
+	}
+	if len(folds) == 0 {
+		return
+	}
+	c.Languages = sloc.Rename(c.Languages, folds)
+	for ext, d := range c.Detectors {
+		c.Detectors[ext] = renamedDetector(d, folds)
+	}
+}
+
+// renamedDetector returns a Detector picking what d does, as renamed by
+// names.
+func renamedDetector(d sloc.Detector, names map[string]string) sloc.Detector {
+	return func(head []byte) string {
+		n := d(head)
+		if r, ok := names[n]; ok {
+			return r
+		}
+		return n
+	}
+}
+
+func main() {
+	flag.Parse()
+	if _, ok := mLangs[*mLang]; !ok {
+		fmt.Fprintf(os.Stderr, "error: -m-lang must be objc, matlab, mathematica or auto, not %q\n", *mLang)
+		exit(2)
+	}
+	if _, ok := plLangs[*plLang]; !ok {
+		fmt.Fprintf(os.Stderr, "error: -pl-lang must be perl, prolog, raku or auto, not %q\n", *plLang)
+		exit(2)
+	}
+	if _, ok := mixedPolicies[*mixed]; !ok {
+		fmt.Fprintf(os.Stderr, "error: -mixed must be code, comment or both, not %q\n", *mixed)
+		exit(2)
+	}
+	if *cocomoModel != "basic" && *cocomoModel != "intermediate" {
+		fmt.Fprintf(os.Stderr, "error: -cocomo-model must be basic or intermediate, not %q\n", *cocomoModel)
+		exit(2)
+	}
+	if _, ok := cocomoModes[*cocomoMode]; !ok {
+		fmt.Fprintf(os.Stderr, "error: -cocomo-mode must be organic, semi-detached or embedded, not %q\n", *cocomoMode)
+		exit(2)
+	}
+	if *group != "" && !isCategory(*group) {
+		fmt.Fprintf(os.Stderr, "error: -group must be config or docs, not %q\n", *group)
+		exit(2)
+	}
+	if err := checkColumns(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		exit(2)
+	}
+	var formats []string
+	for name, set := range map[string]bool{"-json": *useJson || *jsonCompat, "-csv": *useCSV, "-markdown": *useMD} {
+		if set {
+			formats = append(formats, name)
+		}
+	}
+	if len(formats) > 1 {
+		sort.Strings(formats)
+		fmt.Fprintf(os.Stderr, "error: %s are mutually exclusive\n", strings.Join(formats, " and "))
+		exit(2)
+	}
+	if *version {
+		fmt.Printf("sloc %s\n", VERSION)
+		return
+	}
+	if *cpuprofile != "" {
+		f, err := os.Create(*cpuprofile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+			return
+		}
+		pprof.StartCPUProfile(f)
+		defer pprof.StopCPUProfile()
+	}
+
+	args := flag.Args()
+	if *useStdin && (*filesFrom != "" || len(args) > 0) {
+		fmt.Fprintf(os.Stderr, "error: -stdin cannot be combined with -files-from or paths\n")
+		exit(2)
+	}
+	if *filesFrom != "" && len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "error: -files-from cannot be combined with paths\n")
+		exit(2)
+	}
+	if (*useGit || *gitRange != "" || *history) && (*useStdin || *filesFrom != "") {
+		fmt.Fprintf(os.Stderr, "error: -git, -git-diff and -history cannot be combined with -stdin or -files-from\n")
+		exit(2)
+	}
+	if *gitRange != "" && *history {
+		fmt.Fprintf(os.Stderr, "error: -git-diff and -history are mutually exclusive\n")
+		exit(2)
+	}
+	if (*gitRange != "" || *history) && len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "error: -git-diff and -history take a single work tree\n")
+		exit(2)
+	}
+	url := *remoteURL
+	for _, a := range args {
+		if isRemote(a) {
+			url = a
+		}
+	}
+	if url != "" && (len(args) > 1 || (*remoteURL != "" && len(args) > 0) || *useStdin || *filesFrom != "") {
+		fmt.Fprintf(os.Stderr, "error: a remote repository cannot be counted along with other paths, -stdin or -files-from\n")
+		exit(2)
+	}
+	if *remoteRef != "" && url == "" {
+		fmt.Fprintf(os.Stderr, "error: -ref needs -remote or a git URL\n")
+		exit(2)
+	}
+	if url != "" {
+		dir, err := fetchRemote(url, *remoteRef, *quiet)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+			exit(2)
+		}
+		remoteDir = dir
+		defer os.RemoveAll(dir)
+		args = []string{dir}
+	}
+	if len(args) == 0 {
+		args = append(args, `.`)
+	}
+
+	c := newCounter()
+	configs := findConfigs(args[0])
+	if *configFile != "" {
+		configs = []string{*configFile}
+	}
+	for _, f := range configs {
+		cf, err := loadConfig(f)
+		if err == nil {
+			err = cf.apply(c, f)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+			exit(2)
+		}
+		if *verbose {
+			fmt.Fprintf(os.Stderr, "  using %s\n", f)
+		}
+	}
+	if err := mapExtensions(c, extMappings); err != nil {
+		fmt.Fprintf(os.Stderr, "error: -ext %s\n", err.Error())
+		exit(2)
+	}
+	groupLanguages(c)
+	if err := resolveLimits(c); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		exit(2)
+	}
+	if err := filterLanguages(c); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		exit(2)
+	}
+	if *forceLang != "" {
+		l, ok := c.Lookup(*forceLang)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "error: -force-lang: unknown language %q\n", *forceLang)
+			exit(2)
+		}
+		c.Default = &l
+	}
+	if *listLangs {
+		var langs []sloc.Language
+		for _, l := range c.Languages {
+			if c.Exclude == nil || !c.Exclude(l.Name()) {
+				langs = append(langs, l)
+			}
+		}
+		printLanguages(langs)
+		return
+	}
+
+	if *gitRange != "" {
+		r, err := gitDiff(c, args[0], *gitRange)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: -git-diff: %s\n", err.Error())
+			exit(2)
+		}
+		if *useJson {
+			printJSONValue(r)
+		} else {
+			r.print()
+		}
+		return
+	}
+
+	w := &walker{dotfiles: dotfiles(c.Languages)}
+	if *history {
+		series, err := w.history(c, args[0], *interval, *since, *until)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: -history: %s\n", err.Error())
+			exit(2)
+		}
+		if *useJson {
+			printJSONValue(series)
+		} else {
+			printHistoryCSV(series)
+		}
+		return
+	}
+	if *useStdin {
+		args = []string{"-"}
+	} else if *filesFrom != "" {
+		if err := readFileList(w, *filesFrom); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+			exit(1)
+		}
+		args = []string{*filesFrom}
+	} else if *useGit {
+		for _, n := range args {
+			if err := w.addGit(n); err != nil {
+				fmt.Fprintf(os.Stderr, "error: -git: %s\n", err.Error())
+				exit(2)
+			}
+		}
+	} else {
+		for _, n := range args {
+			w.add(n)
+		}
+	}
+
+	w.countArchives(c)
+	if url != "" {
+		args = []string{url} // for the reports to name
+	}
+
+	if *verbose && (w.skippedFiles > 0 || w.skippedDirs > 0) {
+		fmt.Fprintf(os.Stderr, "  skipped %d files and %d directories due to exclusions\n", w.skippedFiles, w.skippedDirs)
+	}
+	if *verbose && w.skippedLinks > 0 {
+		fmt.Fprintf(os.Stderr, "  skipped %d symbolic links; use -follow-symlinks to follow them\n", w.skippedLinks)
+	}
+	if !*quiet && w.prunedDirs > 0 {
+		fmt.Fprintf(os.Stderr, "  skipped %d dependency and build directories; use -no-default-ignores to count them\n", w.prunedDirs)
+	}
+
+	if *useStdin {
+		countStdin(c)
+	} else {
+		count(c, w.files, *workers)
+	}
+	if *verbose {
+		logUnrecognized(c.Unrecognized())
+	}
+	noteDuplicates(c)
+
+	var diff *diffReport
+	if *diffFile != "" {
+		b, err := loadBaseline(*diffFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+			exit(2)
+		}
+		diff = diffResults(*diffFile, b.Languages, c.Results())
+	}
+	if *saveFile != "" {
+		if err := saveBaseline(c, *saveFile); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+			exit(1)
+		}
+	}
+	if *htmlOut != "" {
+		if err := writeHTML(c, args, *htmlOut); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+			exit(1)
+		}
+	}
+
+	if *useJson || *jsonCompat {
+		printJSON(c, args, diff)
+	} else if *useCSV {
+		printCSV(c)
+	} else if *useMD {
+		printMarkdown(c, args)
+		printCocomo(c)
+		printUnrecognized(c.Unrecognized())
+	} else {
+		printInfo(c)
+		printTags(c)
+		printDuplicates(c)
+		printCocomo(c)
+		printUnrecognized(c.Unrecognized())
+	}
+	if diff != nil && !*useJson && !*jsonCompat && !*useCSV {
+		diff.print()
+	}
+	if v := checkLimits(c.Results()); len(v) > 0 {
+		for _, msg := range v {
+			fmt.Fprintf(os.Stderr, "error: %s\n", msg)
+		}
+		exit(1)
+	}
+}
+
+// countStdin counts standard input as the language given by -lang or
+// else the one -stdin-name belongs to.
+func countStdin(c *sloc.Counter) {
+	name := *stdinName
+	if name == "" {
+		name = "-"
+	}
+	var err error
+	if *stdinLang != "" {
+		lang, ok := c.Lookup(*stdinLang)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "error: unknown language %q\n", *stdinLang)
+			exit(2)
+		}
+		err = c.CountReaderAs(name, os.Stdin, lang)
+	} else if *stdinName != "" {
+		err = c.CountReader(name, os.Stdin)
+	} else {
+		fmt.Fprintf(os.Stderr, "error: -stdin needs -lang or -stdin-name\n")
+		exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		exit(1)
+	}
+	if len(c.Results()) == 0 {
+		fmt.Fprintf(os.Stderr, "error: cannot tell the language of %s; use -lang\n", name)
+		exit(2)
+	}
+}
+
+// withoutName returns a copy of langs whose matchers leave out files named
+// name.
+func withoutName(langs []sloc.Language, name string) []sloc.Language {
+	r := make([]sloc.Language, len(langs))
+	for i, l := range langs {
+		var names []string
+		for _, n := range l.Names {
+			if n != name {
+				names = append(names, n)
+			}
+		}
+		l.Names = names
+		r[i] = l
+	}
+	return r
+}
+
+// isCategory reports whether name is one of sloc.Categories, ignoring
+// case.
+func isCategory(name string) bool {
+	for _, cat := range sloc.Categories {
+		if strings.EqualFold(cat, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated flag value.
+func splitList(v string) []string {
+	var s []string
+	for _, f := range strings.Split(v, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			s = append(s, f)
+		}
+	}
+	return s
+}
+
+// filterLanguages has c skip the files of the languages left out by -langs
+// and -exclude-langs. The table stays whole, so that a file still goes to
+// the language it belongs to, and is skipped if that one is left out.
+func filterLanguages(c *sloc.Counter) error {
+	only, excluded := splitList(*onlyLangs), splitList(*excludeLangs)
+	for _, name := range append(append([]string(nil), only...), excluded...) {
+		if _, ok := c.Lookup(name); !ok {
+			var names []string
+			seen := map[string]bool{}
+			for _, l := range c.Languages {
+				if !seen[l.Name()] {
+					seen[l.Name()] = true
+					names = append(names, l.Name())
+				}
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown language %q; the languages are %s", name, strings.Join(names, ", "))
+		}
+	}
+	if len(only) > 0 || len(excluded) > 0 {
+		c.Exclude = func(lang string) bool {
+			return len(only) > 0 && !containsFold(only, lang) || containsFold(excluded, lang)
+		}
+	}
+	return nil
+}
+
+// readFileList adds the files listed in the file name, or on stdin if name
+// is "-".
+func readFileList(w *walker, name string) error {
+	sep := byte('\n')
+	if *nulSep {
+		sep = 0
+	}
+	if name == "-" {
+		return w.addList(os.Stdin, sep)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return w.addList(f, sep)
+}
+
+// printCocomo prints the estimate for the code counted if -cocomo is set.
+func printCocomo(c *sloc.Counter) {
+	if *cocomo {
+		_, total := languageResults(c.Results())
+		estimateCocomo(total.CodeLines, *cocomoModel, *cocomoMode, *cocomoSalary, *cocomoOver, *cocomoEAF).print()
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+func TestGroup(t *testing.T) {
+	saved := *group
+	defer func() { *group = saved }()
+
+	for _, g := range []string{"", "config", "CONFIG"} {
+		*group = g
+		c := newCounter()
+		groupLanguages(c)
+		for _, f := range []string{"a.yml", "b.json", "c.toml", "d.go"} {
+			if err := c.CountReader(f, strings.NewReader("x\n")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r := c.Results()
+		if g == "" {
+			if len(r) != 4 || r["YAML"].FileCount != 1 {
+				t.Errorf("no -group: got %v, want a row per language", r)
+			}
+			continue
+		}
+		if len(r) != 2 || r["Config"].FileCount != 3 || r["Go"].FileCount != 1 {
+			t.Errorf("-group %s: got %v, want Config and Go rows", g, r)
+		}
+	}
+	*group = "docs"
+	c := newCounter()
+	groupLanguages(c)
+	for _, f := range []string{"a.md", "b.rst", "c.tex", "d.yml"} {
+		if err := c.CountReader(f, strings.NewReader("x\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.CountReader("e.cls", strings.NewReader("\\NeedsTeXFormat{LaTeX2e}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if r := c.Results(); len(r) != 2 || r["Docs"].FileCount != 4 || r["YAML"].FileCount != 1 {
+		t.Errorf("-group docs: got %v, want Docs and YAML rows", r)
+	}
+
+	// -ext names languages before they are grouped.
+	*group = "config"
+	c = newCounter()
+	if err := mapExtensions(c, map[string]string{"conf": "Nginx"}); err != nil {
+		t.Fatal(err)
+	}
+	groupLanguages(c)
+	for _, f := range []string{"nginx.conf", "sites/default.conf", "Caddyfile", ".htaccess"} {
+		if err := c.CountReader(f, strings.NewReader("# a\nx;\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r := c.Results(); len(r) != 1 || r["Config"].FileCount != 4 {
+		t.Errorf("-group config -ext conf=Nginx: got %v, want a Config row", r)
+	}
+	if isCategory("code") || !isCategory("Config") || !isCategory("docs") {
+		t.Error("isCategory is wrong")
+	}
+}
+
+func TestWithoutName(t *testing.T) {
+	langs := withoutName(sloc.Languages, "BUILD")
+	for _, f := range []string{"BUILD", "a/BUILD"} {
+		for _, l := range langs {
+			if l.Match(f) {
+				t.Errorf("%s counts as %s", f, l.Name())
+			}
+		}
+	}
+	found := false
+	for _, l := range langs {
+		found = found || l.Match("BUILD.bazel")
+	}
+	if !found {
+		t.Error("BUILD.bazel is no longer counted")
+	}
+	if l, _ := sloc.LanguageByName("Starlark"); !l.Match("BUILD") {
+		t.Error("withoutName changed sloc.Languages")
+	}
+}
+
+func TestPlLang(t *testing.T) {
+	saved := *plLang
+	defer func() { *plLang = saved }()
+
+	prolog := "% Facts.\n:- module(m, []).\n"
+	for lang, want := range map[string]string{"auto": "Prolog", "perl": "Perl", "raku": "Raku"} {
+		*plLang = lang
+		c := newCounter()
+		if err := c.CountReader("a.pl", strings.NewReader(prolog)); err != nil {
+			t.Fatal(err)
+		}
+		if r := c.Results(); r[want].FileCount != 1 {
+			t.Errorf("-pl-lang %s: got %v, want %s", lang, r, want)
+		}
+	}
+}
+
+func TestFilterLanguages(t *testing.T) {
+	savedOnly, savedExcluded := *onlyLangs, *excludeLangs
+	defer func() { *onlyLangs, *excludeLangs = savedOnly, savedExcluded }()
+
+	files := map[string]string{
+		"facts.pl": "% Facts.\n:- module(m, []).\nf(a).\n",
+		"run.pl":   "use strict;\nuse warnings;\nmy $x = 1;\n",
+		"a.go":     "package a\n",
+	}
+	tests := []struct {
+		only, excluded string
+		want           map[string]int // files per language
+	}{
+		{"", "", map[string]int{"Prolog": 1, "Perl": 1, "Go": 1}},
+		{"", "prolog", map[string]int{"Perl": 1, "Go": 1}},
+		{"perl,GO", "", map[string]int{"Perl": 1, "Go": 1}},
+		{"prolog", "", map[string]int{"Prolog": 1}},
+		{"perl,prolog", "perl", map[string]int{"Prolog": 1}},
+	}
+	for _, tt := range tests {
+		*onlyLangs, *excludeLangs = tt.only, tt.excluded
+		c := newCounter()
+		if err := filterLanguages(c); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := c.CountReader(name, strings.NewReader(content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got := map[string]int{}
+		for lang, s := range c.Results() {
+			got[lang] = s.FileCount
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("-langs %q -exclude-langs %q: got %v, want %v", tt.only, tt.excluded, got, tt.want)
+		}
+		if u := c.Unrecognized(); len(u) > 0 {
+			t.Errorf("-langs %q -exclude-langs %q: %v unrecognized", tt.only, tt.excluded, u)
+		}
+	}
+
+	*onlyLangs, *excludeLangs = "go,golang", ""
+	if err := filterLanguages(newCounter()); err == nil || !strings.Contains(err.Error(), `"golang"`) {
+		t.Errorf("an unknown language: got %v", err)
+	}
+}
+
+func TestIncAsPascal(t *testing.T) {
+	saved := *incPascal
+	defer func() { *incPascal = saved }()
+
+	inc := "{$IFDEF DEBUG}\n{ Shared constants. }\nconst Size = 10;\n{$ENDIF}\n"
+	tests := []struct {
+		incPascal bool
+		ext       map[string]string
+		want      string
+	}{
+		{false, nil, ""},
+		{true, nil, "Pascal"},
+		{true, map[string]string{"inc": "PHP"}, "PHP"},
+	}
+	for _, tt := range tests {
+		*incPascal = tt.incPascal
+		c := newCounter()
+		if err := mapExtensions(c, tt.ext); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.CountReader("defs.inc", strings.NewReader(inc)); err != nil {
+			t.Fatal(err)
+		}
+		r := c.Results()
+		switch {
+		case tt.want == "" && (len(r) != 0 || len(c.Unrecognized()) != 1):
+			t.Errorf("-inc-as-pascal=false: got %v, want defs.inc unrecognized", r)
+		case tt.want != "" && r[tt.want].FileCount != 1:
+			t.Errorf("-inc-as-pascal, -ext %v: got %v, want %s", tt.ext, r, tt.want)
+		}
+	}
+	*incPascal = true
+	c := newCounter()
+	if err := c.CountReader("defs.inc", strings.NewReader(inc)); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.Results()["Pascal"]; s.CodeLines != 3 || s.CommentLines != 1 {
+		t.Errorf("got %+v, want the directives as code", s)
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+type LData []LResult
+
+// An LSort orders LData by a column, or by name if col is nil. Ties go
+// to the name that sorts last.
+type LSort struct {
+	LData
+	col     *column
+	reverse bool
+}
+
+func (d LData) Len() int { return len(d) }
+
+func (s LSort) Less(i, j int) bool {
+	if s.reverse {
+		i, j = j, i
+	}
+	a, b := &s.LData[i], &s.LData[j]
+	if s.col == nil {
+		return a.Name < b.Name
+	}
+	if x, y := s.col.value(a), s.col.value(b); x != y {
+		return x > y
+	}
+	return a.Name > b.Name
+}
+
+func (d LData) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
+
+// A column is one of the numbers reported for each language or file.
+type column struct {
+	name, header string
+	value        func(*LResult) int
+	ratio        bool // value is in tenths of a percent
+}
+
+var columns = []column{
+	{"files", "Files", func(r *LResult) int { return r.FileCount }, false},
+	{"code", "Code", func(r *LResult) int { return r.CodeLines }, false},
+	{"comment", "Comment", func(r *LResult) int { return r.CommentLines }, false},
+	{"doc", "Doc", func(r *LResult) int { return r.DocLines }, false},
+	{"blank", "Blank", func(r *LResult) int { return r.BlankLines }, false},
+	{"total", "Total", func(r *LResult) int { return r.TotalLines }, false},
+	{"comment-ratio", "Comment %", func(r *LResult) int { return permille(r.CommentLines, r.CodeLines+r.CommentLines) }, true},
+	{"blank-ratio", "Blank %", func(r *LResult) int { return permille(r.BlankLines, r.TotalLines) }, true},
+	{"max-length", "Max len", func(r *LResult) int { return r.MaxLineLength }, false},
+	{"avg-length", "Avg len", func(r *LResult) int { return int(math.Round(r.averageLineLength())) }, false},
+}
+
+// permille returns n in thousandths of total, rounded, or zero if total
+// is.
+func permille(n, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(float64(n) * 1000 / float64(total)))
+}
+
+func columnByName(name string) (*column, bool) {
+	for i := range columns {
+		if columns[i].name == name {
+			return &columns[i], true
+		}
+	}
+	return nil, false
+}
+
+// shownColumns returns the columns chosen with -columns, leaving out the
+// file count if files is false, and adds those that -doc-comments, -ratios
+// and -line-lengths ask for.
+func shownColumns(files bool) []*column {
+	names := strings.Split(*columnList, ",")
+	for _, extra := range []struct {
+		set   bool
+		names []string
+	}{
+		{*docColumn, []string{"doc"}},
+		{*ratios, []string{"comment-ratio", "blank-ratio"}},
+		{*lineLengths, []string{"max-length", "avg-length"}},
+	} {
+		for _, n := range extra.names {
+			if extra.set && !containsFold(names, n) {
+				names = append(names, n)
+			}
+		}
+	}
+	var cols []*column
+	for _, name := range names {
+		if col, _ := columnByName(strings.TrimSpace(name)); col != nil && (files || col.name != "files") {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// checkColumns validates -sort and -columns.
+func checkColumns() error {
+	if _, ok := columnByName(*sortBy); !ok && *sortBy != "name" {
+		names := []string{"name"}
+		for _, c := range columns {
+			names = append(names, c.name)
+		}
+		return fmt.Errorf("-sort must be one of %s, not %q", strings.Join(names, ", "), *sortBy)
+	}
+	for _, name := range strings.Split(*columnList, ",") {
+		if _, ok := columnByName(strings.TrimSpace(name)); !ok {
+			return fmt.Errorf("unknown column %q in -columns", name)
+		}
+	}
+	return nil
+}
+
+type LResult struct {
+	Name         string
+	FileCount    int
+	CodeLines    int
+	CommentLines int
+	BlankLines   int
+	TotalLines   int
+	DocLines     int
+	Tags         map[string]int
+
+	MaxLineLength, TotalLineLength int
+}
+
+func (r *LResult) Add(a LResult) {
+	r.FileCount += a.FileCount
+	r.CodeLines += a.CodeLines
+	r.CommentLines += a.CommentLines
+	r.BlankLines += a.BlankLines
+	r.TotalLines += a.TotalLines
+	r.DocLines += a.DocLines
+	for t, n := range a.Tags {
+		if r.Tags == nil {
+			r.Tags = map[string]int{}
+		}
+		r.Tags[t] += n
+	}
+	if a.MaxLineLength > r.MaxLineLength {
+		r.MaxLineLength = a.MaxLineLength
+	}
+	r.TotalLineLength += a.TotalLineLength
+}
+
+// averageLineLength returns the mean length of the lines of r, in runes.
+func (r *LResult) averageLineLength() float64 {
+	if r.TotalLines == 0 {
+		return 0
+	}
+	return float64(r.TotalLineLength) / float64(r.TotalLines)
+}
+
+// languageResults returns the sorted per-language results along with
+// their total.
+func languageResults(info map[string]sloc.Stats) (LData, LResult) {
+	d := LData([]LResult{})
+	total := LResult{Name: "Total"}
+	for n, i := range info {
+		r := LResult{n, i.FileCount, i.CodeLines, i.CommentLines, i.BlankLines, i.TotalLines, i.DocLines, i.Tags, i.MaxLineLength, i.TotalLineLength}
+		d = append(d, r)
+		total.Add(r)
+	}
+	col, _ := columnByName(*sortBy)
+	sort.Sort(LSort{d, col, *reverse})
+	return d, total
+}
+
+func printCSV(c *sloc.Counter) {
+	w := csv.NewWriter(os.Stdout)
+	itoa := strconv.Itoa
+	if *byFile {
+		w.Write([]string{"path", "language", "code", "comment", "blank", "total"})
+		for _, f := range c.Files() {
+			w.Write([]string{f.Path, f.Language, itoa(f.CodeLines), itoa(f.CommentLines), itoa(f.BlankLines), itoa(f.TotalLines)})
+		}
+	} else {
+		d, total := languageResults(c.Results())
+		w.Write([]string{"language", "files", "code", "comment", "blank", "total"})
+		for _, i := range append(d, total) {
+			w.Write([]string{i.Name, itoa(i.FileCount), itoa(i.CodeLines), itoa(i.CommentLines), itoa(i.BlankLines), itoa(i.TotalLines)})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+	}
+}
+
+func mdEscape(s string) string {
+	return strings.Replace(s, "|", `\|`, -1)
+}
+
+func fileResult(f sloc.FileStats) LResult {
+	return LResult{f.Path, f.FileCount, f.CodeLines, f.CommentLines, f.BlankLines, f.TotalLines, f.DocLines, f.Tags, f.MaxLineLength, f.TotalLineLength}
+}
+
+// cells formats the values of cols for r with the format f, whose %d
+// ratios fill in as percentages.
+func cells(r *LResult, cols []*column, f string) []string {
+	var s []string
+	for _, c := range cols {
+		if c.ratio {
+			s = append(s, fmt.Sprintf(strings.Replace(f, "%d", "%.1f%%", 1), float64(c.value(r))/10))
+			continue
+		}
+		s = append(s, fmt.Sprintf(f, c.value(r)))
+	}
+	return s
+}
+
+func headers(cols []*column) []string {
+	var s []string
+	for _, c := range cols {
+		s = append(s, c.header)
+	}
+	return s
+}
+
+// tabRow formats a row for a tabwriter.
+func tabRow(s ...string) string {
+	return strings.Join(s, "\t") + "\t\n"
+}
+
+func mdRow(s ...string) string {
+	return "| " + strings.Join(s, " | ") + " |\n"
+}
+
+func printMarkdown(c *sloc.Counter, paths []string) {
+	fmt.Printf("sloc %s: %s\n\n", VERSION, mdEscape(strings.Join(paths, ", ")))
+	align := func(cols []*column) string {
+		var s string
+		for _, c := range cols {
+			s += strings.Repeat("-", len(c.header)+1) + ":|"
+		}
+		return s
+	}
+	if *byFile {
+		cols := shownColumns(false)
+		fmt.Print(mdRow(append([]string{"File", "Language"}, headers(cols)...)...))
+		fmt.Printf("|:-----|:---------|%s\n", align(cols))
+		for _, f := range c.Files() {
+			r := fileResult(f)
+			fmt.Print(mdRow(append([]string{mdEscape(f.Path), f.Language}, cells(&r, cols, "%d")...)...))
+		}
+		if *noSummary {
+			return
+		}
+		fmt.Println()
+	}
+	cols := shownColumns(true)
+	d, total := languageResults(c.Results())
+	fmt.Print(mdRow(append([]string{"Language"}, headers(cols)...)...))
+	fmt.Printf("|:---------|%s\n", align(cols))
+	for _, i := range d {
+		fmt.Print(mdRow(append([]string{mdEscape(i.Name)}, cells(&i, cols, "%d")...)...))
+	}
+	fmt.Print(mdRow(append([]string{"**" + total.Name + "**"}, cells(&total, cols, "**%d**")...)...))
+}
+
+func printFileInfo(c *sloc.Counter) {
+	cols := shownColumns(false)
+	w := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, tabRow(append([]string{"File", "Language"}, headers(cols)...)...))
+	for _, f := range c.Files() {
+		r := fileResult(f)
+		fmt.Fprint(w, tabRow(append([]string{f.Path, f.Language}, cells(&r, cols, "%d")...)...))
+	}
+
+	w.Flush()
+}
+
+func printInfo(c *sloc.Counter) {
+	if *byFile {
+		printFileInfo(c)
+		if *noSummary {
+			return
+		}
+		fmt.Println()
+	}
+	cols := shownColumns(true)
+	w := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, tabRow(append([]string{"Language"}, headers(cols)...)...))
+	d, total := languageResults(c.Results())
+	for _, i := range append(d, total) {
+		fmt.Fprint(w, tabRow(append([]string{i.Name}, cells(&i, cols, "%d")...)...))
+	}
+	// The files skipped get rows of their own, with their number under
+	// Files, or else a line after the table.
+	var notes []string
+	for _, k := range []struct {
+		name string
+		n    int
+	}{{"Binary", c.SkippedBinary()}, {"Minified", c.SkippedMinified()}} {
+		if !*verbose || k.n == 0 {
+			continue
+		}
+		s := make([]string, len(cols))
+		shown := false
+		for i, col := range cols {
+			if col.name == "files" {
+				s[i], shown = strconv.Itoa(k.n), true
+			}
+		}
+		if shown {
+			fmt.Fprint(w, tabRow(append([]string{k.name + " (skipped)"}, s...)...))
+		} else {
+			notes = append(notes, fmt.Sprintf("%s files skipped: %d", k.name, k.n))
+		}
+	}
+
+	w.Flush()
+	for _, n := range notes {
+		fmt.Println(n)
+	}
+}
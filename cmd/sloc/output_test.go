@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+func TestSortLanguages(t *testing.T) {
+	savedSort, savedReverse := *sortBy, *reverse
+	defer func() { *sortBy, *reverse = savedSort, savedReverse }()
+
+	info := map[string]sloc.Stats{
+		"Go":     {FileCount: 3, CodeLines: 50, CommentLines: 5, TotalLines: 60},
+		"C":      {FileCount: 1, CodeLines: 50, CommentLines: 20, TotalLines: 70},
+		"Python": {FileCount: 2, CodeLines: 10, CommentLines: 30, TotalLines: 45},
+	}
+	tests := []struct {
+		sort    string
+		reverse bool
+		want    []string
+	}{
+		{"code", false, []string{"Go", "C", "Python"}}, // a tie goes to the name that sorts last
+		{"code", true, []string{"Python", "C", "Go"}},
+		{"comment", false, []string{"Python", "C", "Go"}},
+		{"files", false, []string{"Go", "Python", "C"}},
+		{"name", false, []string{"C", "Go", "Python"}},
+		{"name", true, []string{"Python", "Go", "C"}},
+	}
+	for _, tt := range tests {
+		*sortBy, *reverse = tt.sort, tt.reverse
+		d, _ := languageResults(info)
+		var got []string
+		for _, r := range d {
+			got = append(got, r.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("-sort %s, reverse %v: got %v, want %v", tt.sort, tt.reverse, got, tt.want)
+		}
+	}
+}
+
+func TestCheckColumns(t *testing.T) {
+	savedSort, savedColumns := *sortBy, *columnList
+	defer func() { *sortBy, *columnList = savedSort, savedColumns }()
+
+	tests := []struct {
+		sort, columns string
+		ok            bool
+	}{
+		{"code", "files,code,comment,blank,total", true},
+		{"name", "code, total", true},
+		{"lines", "code", false},
+		{"code", "code,lines", false},
+	}
+	for _, tt := range tests {
+		*sortBy, *columnList = tt.sort, tt.columns
+		if err := checkColumns(); (err == nil) != tt.ok {
+			t.Errorf("-sort %s -columns %s: got %v", tt.sort, tt.columns, err)
+		}
+	}
+	*columnList = "total,code"
+	if got := headers(shownColumns(true)); !reflect.DeepEqual(got, []string{"Total", "Code"}) {
+		t.Errorf("shown columns %v", got)
+	}
+}
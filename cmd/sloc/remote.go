@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// scpURL matches the scp-like syntax of git, as in git@github.com:foo/bar.
+var scpURL = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+
+// isRemote reports whether the argument arg is the URL of a git
+// repository rather than a path.
+func isRemote(arg string) bool {
+	for _, p := range []string{"https://", "http://", "ssh://", "git://", "file://"} {
+		if strings.HasPrefix(arg, p) {
+			return true
+		}
+	}
+	return scpURL.MatchString(arg)
+}
+
+// remoteDir is the temporary directory the remote repository is fetched
+// into, which exit removes as main would on returning.
+var remoteDir string
+
+// exit removes remoteDir, if any, and exits with the status code.
+func exit(code int) {
+	if remoteDir != "" {
+		os.RemoveAll(remoteDir)
+	}
+	os.Exit(code)
+}
+
+// displayPath returns the path n as the reports name it: relative to
+// remoteDir if it is in there.
+func displayPath(n string) string {
+	if remoteDir == "" {
+		return n
+	}
+	return strings.TrimPrefix(n, remoteDir+"/")
+}
+
+// countFile counts the file n under its displayPath.
+func countFile(c *sloc.Counter, n string) error {
+	if remoteDir == "" {
+		return c.CountFile(n)
+	}
+	f, err := os.Open(n)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.CountReader(displayPath(n), f)
+}
+
+// fetchRemote fetches the tree of ref, or of the default branch if ref is
+// empty, from the git repository at url into a new temporary directory,
+// which it returns. The ambient git configuration supplies any
+// credentials, without prompting for them. Git's progress goes to stderr
+// unless quiet is set.
+func fetchRemote(url, ref string, quiet bool) (string, error) {
+	dir, err := ioutil.TempDir("", "sloc-")
+	if err != nil {
+		return "", err
+	}
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "  fetching %s\n", url)
+	}
+	progress := "--progress"
+	if quiet {
+		progress = "--quiet"
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--depth", "1", progress, url, ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		var msg bytes.Buffer
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		cmd.Stderr = &msg
+		if !quiet {
+			cmd.Stderr = io.MultiWriter(os.Stderr, &msg)
+		}
+		if err := cmd.Run(); err != nil {
+			os.RemoveAll(dir)
+			return "", remoteError(url, ref, msg.String(), err)
+		}
+	}
+	return dir, nil
+}
+
+// remoteError explains why git failed to fetch ref from url, given what
+// it wrote to stderr.
+func remoteError(url, ref, msg string, err error) error {
+	if _, ok := err.(*exec.ExitError); !ok {
+		return fmt.Errorf("git is needed to count a remote repository: %s", err)
+	}
+	// The first fatal line says what went wrong; the rest is advice.
+	why := err.Error()
+	for _, line := range strings.Split(msg, "\n") {
+		if strings.HasPrefix(line, "fatal: ") {
+			why = strings.TrimSpace(line)
+			break
+		}
+	}
+	has := func(s ...string) bool {
+		for _, t := range s {
+			if strings.Contains(msg, t) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("couldn't find remote ref", "not our ref", "unadvertised object"):
+		return fmt.Errorf("%s has no branch, tag or commit %q; check -ref", url, ref)
+	case has("could not read Username", "terminal prompts disabled", "Authentication failed", "Permission denied"):
+		return fmt.Errorf("cannot authenticate to %s; set up a git credential helper, or an SSH key for an SSH URL, so that git can fetch it without asking (%s)", url, why)
+	case has("Could not resolve host", "Connection refused", "timed out", "Network is unreachable", "unable to access"):
+		return fmt.Errorf("cannot reach %s; check the URL and the network (%s)", url, why)
+	case has("does not appear to be a git repository", "Repository not found", "not found"):
+		return fmt.Errorf("%s is not a git repository, or not one you can read (%s)", url, why)
+	}
+	return fmt.Errorf("git fetch %s: %s", url, why)
+}
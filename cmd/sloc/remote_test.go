@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsRemote(t *testing.T) {
+	for arg, want := range map[string]bool{
+		"https://github.com/foo/bar.git": true,
+		"ssh://git@host/foo/bar":         true,
+		"git@github.com:foo/bar.git":     true,
+		"file:///srv/git/bar":            true,
+		".":                              false,
+		"src/main.go":                    false,
+		"C:/src":                         false,
+		"dir:with/colon":                 false,
+	} {
+		if got := isRemote(arg); got != want {
+			t.Errorf("isRemote(%q) is %v, want %v", arg, got, want)
+		}
+	}
+}
+
+func TestFetchRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("no git")
+	}
+	src, err := ioutil.TempDir("", "sloc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=a", "-c", "user.email=a@example.com", "commit", "-q", "-m", "one"},
+		{"tag", "v1"},
+	} {
+		if args[0] == "add" {
+			if err := ioutil.WriteFile(filepath.Join(src, "a.go"), []byte("package a\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if out, err := exec.Command("git", append([]string{"-C", src}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	url := "file://" + filepath.ToSlash(src)
+	for _, ref := range []string{"", "v1"} {
+		dir, err := fetchRemote(url, ref, true)
+		if err != nil {
+			t.Fatalf("-ref %q: %v", ref, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "a.go")); err != nil {
+			t.Errorf("-ref %q: %v", ref, err)
+		}
+		os.RemoveAll(dir)
+	}
+	if _, err := fetchRemote(url, "v2", true); err == nil || !strings.Contains(err.Error(), "check -ref") {
+		t.Errorf("a missing ref: got %v", err)
+	}
+	if _, err := fetchRemote(url+"-gone", "", true); err == nil || !strings.Contains(err.Error(), "not a git repository") {
+		t.Errorf("a missing repository: got %v", err)
+	}
+}
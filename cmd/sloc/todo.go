@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// printTags prints how many of each -todo-tags tag the comments of each
+// language hold if -todo is set, and with -verbose where they are.
+func printTags(c *sloc.Counter) {
+	if !*todo {
+		return
+	}
+	tags := splitList(*todoTags)
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, tabRow(append([]string{"Language"}, tags...)...))
+	d, total := languageResults(c.Results())
+	for _, i := range append(d, total) {
+		if len(i.Tags) == 0 && i.Name != total.Name {
+			continue
+		}
+		row := []string{i.Name}
+		for _, t := range tags {
+			row = append(row, strconv.Itoa(i.Tags[t]))
+		}
+		fmt.Fprint(w, tabRow(row...))
+	}
+	w.Flush()
+
+	if !*verbose {
+		return
+	}
+	files := c.Files()
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	for _, f := range files {
+		for _, t := range f.TagLines {
+			fmt.Printf("%s:%d: %s\n", f.Path, t.Line, t.Tag)
+		}
+	}
+}
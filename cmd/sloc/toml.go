@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML parses the subset of TOML a config file needs: tables, arrays
+// of tables, and keys holding strings, booleans, integers or arrays of
+// those.
+func parseTOML(data string) (map[string]interface{}, error) {
+	p := &tomlParser{s: data, line: 1}
+	root := map[string]interface{}{}
+	cur := root
+	for {
+		p.skipSpace(true)
+		if p.eof() {
+			return root, nil
+		}
+		switch {
+		case strings.HasPrefix(p.s[p.i:], "[["):
+			p.i += 2
+			name, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]]"); err != nil {
+				return nil, err
+			}
+			list, _ := root[name].([]interface{})
+			if _, ok := root[name]; ok && list == nil {
+				return nil, p.errorf("%s is not an array of tables", name)
+			}
+			cur = map[string]interface{}{}
+			root[name] = append(list, cur)
+		case p.s[p.i] == '[':
+			p.i++
+			name, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			if _, ok := root[name]; ok {
+				return nil, p.errorf("table %s defined twice", name)
+			}
+			cur = map[string]interface{}{}
+			root[name] = cur
+		default:
+			k, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace(false)
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := cur[k]; ok {
+				return nil, p.errorf("key %s defined twice", k)
+			}
+			cur[k] = v
+		}
+		p.skipSpace(false)
+		if !p.eof() && p.s[p.i] != '\n' {
+			return nil, p.errorf("unexpected %q", p.s[p.i])
+		}
+	}
+}
+
+type tomlParser struct {
+	s    string
+	i    int
+	line int
+}
+
+func (p *tomlParser) eof() bool { return p.i >= len(p.s) }
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips blanks and comments, and newlines too if nl is set.
+func (p *tomlParser) skipSpace(nl bool) {
+	for !p.eof() {
+		switch c := p.s[p.i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.i++
+		case c == '\n' && nl:
+			p.i++
+			p.line++
+		case c == '#':
+			for !p.eof() && p.s[p.i] != '\n' {
+				p.i++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) expect(s string) error {
+	p.skipSpace(false)
+	if !strings.HasPrefix(p.s[p.i:], s) {
+		return p.errorf("expected %q", s)
+	}
+	p.i += len(s)
+	return nil
+}
+
+func (p *tomlParser) key() (string, error) {
+	p.skipSpace(false)
+	if !p.eof() && (p.s[p.i] == '"' || p.s[p.i] == '\'') {
+		return p.str()
+	}
+	j := p.i
+	for j < len(p.s) && (isKeyByte(p.s[j])) {
+		j++
+	}
+	if j == p.i {
+		return "", p.errorf("expected a key")
+	}
+	k := p.s[p.i:j]
+	p.i = j
+	return k, nil
+}
+
+func isKeyByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func (p *tomlParser) str() (string, error) {
+	q := p.s[p.i]
+	p.i++
+	var b strings.Builder
+	for {
+		if p.eof() || p.s[p.i] == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.s[p.i]
+		p.i++
+		switch {
+		case c == q:
+			return b.String(), nil
+		case c == '\\' && q == '"' && !p.eof():
+			e := p.s[p.i]
+			p.i++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(e)
+			default:
+				return "", p.errorf("unknown escape \\%c", e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *tomlParser) value() (interface{}, error) {
+	p.skipSpace(false)
+	if p.eof() {
+		return nil, p.errorf("expected a value")
+	}
+	switch c := p.s[p.i]; {
+	case c == '"' || c == '\'':
+		return p.str()
+	case c == '[':
+		p.i++
+		a := []interface{}{}
+		for {
+			p.skipSpace(true)
+			if p.eof() {
+				return nil, p.errorf("unterminated array")
+			}
+			if p.s[p.i] == ']' {
+				p.i++
+				return a, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+			p.skipSpace(true)
+			if !p.eof() && p.s[p.i] == ',' {
+				p.i++
+			} else if p.eof() || p.s[p.i] != ']' {
+				return nil, p.errorf("expected , or ] in array")
+			}
+		}
+	}
+	j := p.i
+	for j < len(p.s) && isKeyByte(p.s[j]) {
+		j++
+	}
+	if j == p.i {
+		return nil, p.errorf("unexpected %q", p.s[p.i])
+	}
+	w := p.s[p.i:j]
+	p.i = j
+	switch w {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if n, err := strconv.Atoi(w); err == nil {
+		return n, nil
+	}
+	return nil, p.errorf("invalid value %q", w)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// An extFiles is a group of unrecognized files sharing an extension.
+type extFiles struct {
+	Ext   string
+	Files []string
+}
+
+// byExtension groups files by extension, the largest groups first. Files
+// without an extension are grouped under "<none>".
+func byExtension(files []string) []extFiles {
+	idx := map[string]int{}
+	var g []extFiles
+	for _, f := range files {
+		ext := path.Ext(f)
+		if ext == "" {
+			ext = "<none>"
+		}
+		i, ok := idx[ext]
+		if !ok {
+			i = len(g)
+			idx[ext] = i
+			g = append(g, extFiles{Ext: ext})
+		}
+		g[i].Files = append(g[i].Files, f)
+	}
+	sort.Slice(g, func(i, j int) bool {
+		if len(g[i].Files) == len(g[j].Files) {
+			return g[i].Ext < g[j].Ext
+		}
+		return len(g[i].Files) > len(g[j].Files)
+	})
+	return g
+}
+
+// logUnrecognized lists the files that matched no language on stderr.
+func logUnrecognized(files []string) {
+	if len(files) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "  matched no language:\n")
+	for _, g := range byExtension(files) {
+		fmt.Fprintf(os.Stderr, "    %s (%d)\n", g.Ext, len(g.Files))
+		for _, f := range g.Files {
+			fmt.Fprintf(os.Stderr, "      %s\n", f)
+		}
+	}
+}
+
+// topExtensions is how many extensions the unrecognized summary names.
+const topExtensions = 5
+
+// thousands formats n with commas between groups of three digits.
+func thousands(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// printUnrecognized summarizes the files that matched no language, and
+// lists them with -show-unrecognized.
+func printUnrecognized(files []string) {
+	if len(files) == 0 {
+		return
+	}
+	g := byExtension(files)
+	var top []string
+	for i := 0; i < len(g) && i < topExtensions; i++ {
+		top = append(top, fmt.Sprintf("%s (%s)", g[i].Ext, thousands(len(g[i].Files))))
+	}
+	fmt.Printf("\nUnrecognized: %s files, top extensions: %s\n", thousands(len(files)), strings.Join(top, ", "))
+	if *showUnrec {
+		for _, f := range files {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+}
+
+type jsonUnrecognized struct {
+	Files      int       `json:"files"`
+	Extensions []jsonExt `json:"extensions"`
+	Paths      []string  `json:"paths,omitempty"`
+}
+
+// A jsonExt counts the unrecognized files with an extension, which is
+// empty for files without one.
+type jsonExt struct {
+	Ext   string `json:"ext"`
+	Files int    `json:"files"`
+}
+
+func newJSONUnrecognized(files []string) *jsonUnrecognized {
+	u := &jsonUnrecognized{Files: len(files), Extensions: []jsonExt{}}
+	for _, g := range byExtension(files) {
+		ext := g.Ext
+		if ext == "<none>" {
+			ext = ""
+		}
+		u.Extensions = append(u.Extensions, jsonExt{ext, len(g.Files)})
+	}
+	if *showUnrec {
+		u.Paths = files
+	}
+	return u
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+// A walker collects the files to count from the command-line arguments.
+type walker struct {
+	files    []string
+	archives []string // counted entry by entry
+
+	skippedFiles, skippedDirs int
+	prunedDirs                int // by defaultIgnores
+	skippedLinks              int
+
+	seen map[fileKey]bool // the files and directories walked, with -follow-symlinks
+
+	// dotfiles are the hidden files that languages name, such as .bashrc,
+	// which are walked even though other hidden files are not.
+	dotfiles map[string]bool
+}
+
+// dotfiles returns the names of hidden files that langs match by name.
+func dotfiles(langs []sloc.Language) map[string]bool {
+	m := map[string]bool{}
+	for _, l := range langs {
+		for _, n := range l.Names {
+			if strings.HasPrefix(n, ".") {
+				m[n] = true
+			}
+		}
+	}
+	return m
+}
+
+type fileKey struct{ dev, ino uint64 }
+
+// visited reports whether the file fi has been walked before, and records
+// it if not.
+func (w *walker) visited(fi os.FileInfo) bool {
+	k, ok := fileID(fi)
+	if !ok {
+		return false
+	}
+	if w.seen[k] {
+		return true
+	}
+	if w.seen == nil {
+		w.seen = map[fileKey]bool{}
+	}
+	w.seen[k] = true
+	return false
+}
+
+// defaultIgnores names the dependency, build and version control
+// directories that are skipped unless -no-default-ignores is given.
+var defaultIgnores = map[string]bool{
+	".git":             true,
+	".hg":              true,
+	".svn":             true,
+	".terraform":       true,
+	"__pycache__":      true,
+	"bower_components": true,
+	"node_modules":     true,
+	"target":           true,
+	"vendor":           true,
+}
+
+// A byteSize is a flag.Value for a number of bytes, optionally with a K,
+// M or G suffix.
+type byteSize int64
+
+func (b *byteSize) String() string { return strconv.FormatInt(int64(*b), 10) }
+
+func (b *byteSize) Set(v string) error {
+	mul := int64(1)
+	if v != "" {
+		switch v[len(v)-1] {
+		case 'k', 'K':
+			mul = 1 << 10
+		case 'm', 'M':
+			mul = 1 << 20
+		case 'g', 'G':
+			mul = 1 << 30
+		}
+	}
+	if mul > 1 {
+		v = v[:len(v)-1]
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid size %q", v)
+	}
+	*b = byteSize(n * mul)
+	return nil
+}
+
+// maxFileSize is the -max-file-size limit, or zero for no limit.
+var maxFileSize byteSize
+
+// addFile adds the regular file n unless it is larger than -max-file-size.
+// Archives are added whatever their size, for their entries to be counted.
+func (w *walker) addFile(n string, fi os.FileInfo) {
+	if isArchive(n) {
+		w.archives = append(w.archives, n)
+		return
+	}
+	if maxFileSize > 0 && fi.Size() > int64(maxFileSize) {
+		fmt.Fprintf(os.Stderr, "  ! skipped, %d bytes is over -max-file-size\n", fi.Size())
+		fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+		return
+	}
+	w.files = append(w.files, n)
+}
+
+func (w *walker) add(n string) {
+	var ig ignoreStack
+	if !*noGitignore {
+		ig = parentIgnores(n)
+	}
+	w.walk(path.Clean(n), n, ig, true)
+}
+
+// addList adds the files named in r, separated by sep, without walking
+// any directories. The caller chose the files, so nothing is ignored or
+// excluded.
+func (w *walker) addList(r io.Reader, sep byte) error {
+	br := bufio.NewReader(r)
+	for {
+		n, err := br.ReadString(sep)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		n = strings.TrimSuffix(n, string(sep))
+		if sep == '\n' {
+			n = strings.TrimSuffix(n, "\r")
+		}
+		if n != "" {
+			fi, serr := os.Stat(n)
+			switch {
+			case serr != nil:
+				fmt.Fprintf(os.Stderr, "  ! Stat %s\n", serr)
+				fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+			case fi.Mode()&os.ModeType != 0:
+				fmt.Fprintf(os.Stderr, "  ! not a regular file\n")
+				fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+			default:
+				w.addFile(n, fi)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// walk collects the files under n, which was reached from the argument
+// root. Paths matched by ig or by the exclusion patterns are skipped unless
+// they were named explicitly. So are symbolic links, unless -follow-symlinks
+// is given, in which case each file or directory is walked only once.
+func (w *walker) walk(root, n string, ig ignoreStack, explicit bool) {
+	fi, err := os.Lstat(n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  ! Stat %s\n", err)
+		fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+		return
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		if !explicit && !*followLinks {
+			w.skippedLinks++
+			return
+		}
+		if fi, err = os.Stat(n); err != nil {
+			fmt.Fprintf(os.Stderr, "  ! broken symlink: %s\n", err)
+			fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+			return
+		}
+	}
+	if !explicit && (ig.Ignored(n, fi.IsDir()) || w.excluded(relPath(root, n), fi.IsDir())) {
+		return
+	}
+	if !explicit && fi.IsDir() && !*noDefIgnores && defaultIgnores[fi.Name()] {
+		w.prunedDirs++
+		return
+	}
+	if *followLinks && w.visited(fi) {
+		return
+	}
+	if fi.IsDir() {
+		fs, err := ioutil.ReadDir(n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  ! ReadDir %s\n", err)
+			fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+			return
+		}
+		for _, f := range fs {
+			if f.Name() == ".nosloc" {
+				return
+			}
+		}
+		if !*noGitignore {
+			ig = ig.push(n)
+		}
+		for _, f := range fs {
+			if f.Name()[0] != '.' || (!f.IsDir() && w.dotfiles[f.Name()]) {
+				w.walk(root, path.Join(n, f.Name()), ig, false)
+			}
+		}
+		return
+	}
+	if fi.Mode()&os.ModeType == 0 {
+		w.addFile(n, fi)
+		return
+	}
+	if *verbose {
+		fmt.Fprintf(os.Stderr, "  ! not a regular file: %s\n", fi.Mode())
+		fmt.Fprintf(os.Stderr, "  ! %s\n", n)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/uk702/sloc/sloc"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want byteSize
+		ok   bool
+	}{
+		{"0", 0, true},
+		{"512", 512, true},
+		{"4k", 4 << 10, true},
+		{"10M", 10 << 20, true},
+		{"2G", 2 << 30, true},
+		{"", 0, false},
+		{"K", 0, false},
+		{"-1", 0, false},
+		{"1.5M", 0, false},
+	}
+	for _, tt := range tests {
+		var b byteSize
+		err := b.Set(tt.in)
+		if (err == nil) != tt.ok || tt.ok && b != tt.want {
+			t.Errorf("Set(%q): got %d, %v; want %d, ok %v", tt.in, b, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMaxFileSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sloc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	small, big := filepath.Join(dir, "small.go"), filepath.Join(dir, "big.go")
+	if err := ioutil.WriteFile(small, []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(big, []byte(strings.Repeat("// a\n", 300)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := maxFileSize
+	defer func() { maxFileSize = saved }()
+	for _, tt := range []struct {
+		limit byteSize
+		want  int
+	}{
+		{0, 2},
+		{1 << 10, 1},
+		{1500, 2},
+	} {
+		maxFileSize = tt.limit
+		w := &walker{}
+		if err := w.addList(strings.NewReader(small+"\n"+big+"\n"), '\n'); err != nil {
+			t.Fatal(err)
+		}
+		if len(w.files) != tt.want {
+			t.Errorf("-max-file-size %d: got %v, want %d files", tt.limit, w.files, tt.want)
+		}
+	}
+}
+
+func TestDotfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sloc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, ".zshrc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []string{".bashrc", ".hidden", "a.go", ".zshrc/.profile"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, n), []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := &walker{dotfiles: dotfiles(sloc.Languages)}
+	w.add(dir)
+	got := strings.Join(w.files, " ")
+	if want := filepath.Join(dir, ".bashrc") + " " + filepath.Join(dir, "a.go"); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/uk702/sloc
+
+go 1.16
@@ -0,0 +1,383 @@
+// Package sloc counts the lines of code, comments and blanks in source
+// files.
+package sloc
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Counter accumulates statistics per language over the files it is
+// given. It is safe for concurrent use.
+type Counter struct {
+	// Languages is the table the counter matches files against.
+	Languages []Language
+
+	// Detectors choose between the languages matching a file, keyed by
+	// the file's extension.
+	Detectors map[string]Detector
+
+	// Default, if set, is the language of files that match no other, not
+	// even by a shebang line.
+	Default *Language
+
+	// Exclude, if set, skips the files whose language it reports. The
+	// language is chosen from the whole table first, so that leaving one
+	// out does not hand its files to another matching them too.
+	Exclude func(lang string) bool
+
+	// FoldNotebooks counts Jupyter notebooks under the language of their
+	// kernel rather than as "Jupyter (Python)" and so on.
+	FoldNotebooks bool
+
+	// SplitErlangTests counts the -ifdef(TEST). sections of Erlang files
+	// as ErlangTest rather than Erlang.
+	SplitErlangTests bool
+
+	// MinifiedLineLength, if positive, is the average line length above
+	// which JavaScript files count as MinifiedJS. Files named *.min.js
+	// always do.
+	MinifiedLineLength int
+
+	// SkipMinified skips the files that would count as MinifiedJS.
+	SkipMinified bool
+
+	// FindDuplicates makes the counter find the files with the same
+	// content, which Duplicates returns.
+	FindDuplicates bool
+
+	// SkipDuplicates counts each content only once, as the copy with the
+	// least path, whatever the order the files come in.
+	SkipDuplicates bool
+
+	// SplitGenerated counts generated files, such as those marked so in
+	// their first lines, as "Go (generated)" and so on.
+	SplitGenerated bool
+
+	// Options adjusts how lines are counted.
+	Options Options
+
+	// CountBinary makes the counter count files that look binary, which
+	// it otherwise skips.
+	CountBinary bool
+
+	// KeepFiles makes the counter retain the statistics of each file.
+	KeepFiles bool
+
+	// KeepLines makes the counter retain, with KeepFiles, what each line
+	// of each file counts as, in FileStats.Lines. Erlang files split by
+	// SplitErlangTests and notebooks have none.
+	KeepLines bool
+
+	// Log, if set, receives diagnostics such as files attributed to a
+	// language by their shebang line.
+	Log func(format string, args ...interface{})
+
+	mu     sync.Mutex
+	stats  map[string]*Stats
+	files  []FileStats
+	binary int
+	none   []string // the files matching no language
+
+	minified int // the files skipped by SkipMinified
+	dups     map[[sha256.Size]byte]*Duplicate
+}
+
+// NewCounter returns a Counter using the default Languages.
+func NewCounter() *Counter {
+	d := make(map[string]Detector, len(Detectors))
+	for ext, f := range Detectors {
+		d[ext] = f
+	}
+	return &Counter{Languages: Languages, Detectors: d}
+}
+
+// headSize is how much of a file is looked at for a shebang line.
+const headSize = 256
+
+// match returns the languages a file name belongs to, in table order.
+func (c *Counter) match(fname string) []Language {
+	var langs []Language
+	for _, lang := range c.Languages {
+		if lang.Match(fname) {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// pick chooses among the languages matching a file. A detector for the
+// extension gets to look at the content; otherwise the first in the table
+// wins, so that the more specific entries such as GoTest come before the
+// general ones.
+func (c *Counter) pick(fname string, langs []Language, content []byte) Language {
+	if d, ok := c.Detectors[path.Ext(fname)]; ok && len(langs) > 1 {
+		if len(content) > detectSize {
+			content = content[:detectSize]
+		}
+		if name := d(content); name != "" {
+			for _, l := range langs {
+				if l.Name() == name {
+					return l
+				}
+			}
+		}
+	}
+	return langs[0]
+}
+
+// CountFile counts the file at fname. Files that match no language are
+// skipped.
+func (c *Counter) CountFile(fname string) error {
+	f, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.CountReader(fname, f)
+}
+
+// CountReader counts the content of r as if it were the file name. Content
+// in UTF-16 is converted to UTF-8 first.
+func (c *Counter) CountReader(name string, r io.Reader) error {
+	return c.countReader(name, name, r)
+}
+
+// CountEntry counts the content of r, the file name within the archive
+// named archive, as CountReader does. Its statistics are those of the path
+// archive!name.
+func (c *Counter) CountEntry(archive, name string, r io.Reader) error {
+	return c.countReader(archive+"!"+name, name, r)
+}
+
+// countReader counts the content of r as if it were the file name, under
+// the path p.
+func (c *Counter) countReader(p, name string, r io.Reader) error {
+	if path.Ext(name) == ".ipynb" {
+		return c.countNotebook(p, r)
+	}
+	br := decode(bufio.NewReaderSize(r, detectSize))
+	head, err := br.Peek(detectSize)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	langs := c.match(name)
+	if len(langs) == 0 {
+		h := head
+		if len(h) > headSize {
+			h = h[:headSize]
+		}
+		lang, ok := shebangLanguage(h, c.Languages)
+		switch {
+		case ok:
+			c.logf("  %s: %s (shebang)\n", p, lang.Name())
+		case c.Default != nil:
+			lang = *c.Default
+		default:
+			c.mu.Lock()
+			c.none = append(c.none, p)
+			c.mu.Unlock()
+			return nil
+		}
+		langs = []Language{lang}
+	}
+	if !c.CountBinary && isBinary(head) {
+		c.logf("  %s: binary, skipped\n", p)
+		c.mu.Lock()
+		c.binary++
+		c.mu.Unlock()
+		return nil
+	}
+	l := c.pick(name, langs, head)
+	if c.Exclude != nil && c.Exclude(l.Name()) {
+		c.logf("  %s: %s, excluded\n", p, l.Name())
+		return nil
+	}
+	lang, ok := c.variant(name, l, head)
+	if !ok {
+		return nil
+	}
+	return c.count(p, br, lang)
+}
+
+// CountReaderAs counts the content of r, named name, as the language lang.
+func (c *Counter) CountReaderAs(name string, r io.Reader, lang Language) error {
+	return c.count(name, r, lang)
+}
+
+// Lookup returns the language in the counter's table with the given name,
+// ignoring case.
+func (c *Counter) Lookup(name string) (Language, bool) {
+	for _, l := range c.Languages {
+		if strings.EqualFold(l.Name(), name) {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
+
+func (c *Counter) count(fname string, r io.Reader, l Language) error {
+	var h *lineEndHash
+	if c.FindDuplicates || c.SkipDuplicates {
+		h = newLineEndHash()
+		r = io.TeeReader(r, h)
+	}
+	var files []FileStats
+	var err error
+	if c.SplitErlangTests && l.Name() == "Erlang" {
+		files, err = c.countErlang(fname, r, l)
+	} else {
+		files, err = c.countFile(fname, r, l)
+	}
+	if err != nil {
+		return err
+	}
+	if h != nil {
+		hold, of := c.duplicate(fname, h.sum(), files)
+		if of != "" {
+			c.logf("  %s: same content as %s\n", fname, of)
+		}
+		if hold {
+			return nil
+		}
+	}
+	for _, f := range files {
+		c.add(f)
+	}
+	return nil
+}
+
+// countFile counts the content of r, named fname, as the language l.
+func (c *Counter) countFile(fname string, r io.Reader, l Language) ([]FileStats, error) {
+	f := FileStats{Path: fname, Language: l.Name()}
+	o := &c.Options
+	if c.KeepFiles && (len(o.Tags) > 0 || c.KeepLines) {
+		fo := *o
+		if len(o.Tags) > 0 {
+			fo.tagLine = func(tag string, line int) {
+				f.TagLines = append(f.TagLines, TagLine{tag, line})
+			}
+		}
+		if c.KeepLines {
+			fo.lineClass = func(cl Class) {
+				f.Lines = append(f.Lines, cl)
+			}
+		}
+		o = &fo
+	}
+	if err := l.UpdateReader(r, &f.Stats, o); err != nil {
+		return nil, err
+	}
+	return []FileStats{f}, nil
+}
+
+// add accumulates the statistics of one file.
+func (c *Counter) add(f FileStats) {
+	c.logf("  %-12s %s  %d code\n", f.Language, f.Path, f.CodeLines)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stats == nil {
+		c.stats = map[string]*Stats{}
+	}
+	s, ok := c.stats[f.Language]
+	if !ok {
+		s = &Stats{}
+		c.stats[f.Language] = s
+	}
+	s.Add(&f.Stats)
+	if c.KeepFiles {
+		c.files = append(c.files, f)
+	}
+}
+
+func (c *Counter) logf(format string, args ...interface{}) {
+	if c.Log != nil {
+		c.Log(format, args...)
+	}
+}
+
+// Results returns the statistics accumulated so far for each language.
+func (c *Counter) Results() map[string]Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := make(map[string]Stats, len(c.stats))
+	for n, s := range c.stats {
+		var t Stats // a copy of s, Tags and all
+		t.Add(s)
+		r[n] = t
+	}
+	for _, d := range c.dups {
+		for i := range d.files {
+			f := &d.files[i]
+			t := r[f.Language]
+			t.Add(&f.Stats)
+			r[f.Language] = t
+		}
+	}
+	return r
+}
+
+// Reset discards what the counter has accumulated, so that it can count
+// afresh.
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats, c.files, c.binary, c.none = nil, nil, 0, nil
+	c.minified, c.dups = 0, nil
+}
+
+// SkippedBinary returns how many files were skipped for looking binary.
+func (c *Counter) SkippedBinary() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.binary
+}
+
+// Unrecognized returns the files found to match no language, sorted.
+func (c *Counter) Unrecognized() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	none := append([]string(nil), c.none...)
+	sort.Strings(none)
+	return none
+}
+
+// Files returns the statistics of each file counted when KeepFiles is set,
+// sorted by code lines descending.
+func (c *Counter) Files() []FileStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	files := append([]FileStats(nil), c.files...)
+	if c.KeepFiles {
+		for _, d := range c.dups {
+			files = append(files, d.files...)
+		}
+	}
+	sort.Sort(byCode(files))
+	return files
+}
+
+type byCode []FileStats
+
+func (d byCode) Len() int { return len(d) }
+
+func (d byCode) Less(i, j int) bool {
+	if d[i].CodeLines == d[j].CodeLines {
+		if d[i].Path == d[j].Path {
+			return d[i].Language < d[j].Language
+		}
+		return d[i].Path < d[j].Path
+	}
+	return d[i].CodeLines > d[j].CodeLines
+}
+
+func (d byCode) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
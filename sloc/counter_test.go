@@ -0,0 +1,156 @@
+package sloc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCounter(t *testing.T) {
+	var files []string
+	python := 0
+	for _, tt := range languageTests {
+		files = append(files, filepath.Join("testdata", tt.file))
+		if tt.lang == "Python" {
+			python++
+		}
+	}
+	files = append(files, filepath.Join("testdata", "run-tests"))
+
+	one := NewCounter()
+	for _, f := range files {
+		if err := one.CountFile(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Files counted concurrently, and through CountReader, add up the same.
+	many := NewCounter()
+	var wg sync.WaitGroup
+	for _, f := range files {
+		wg.Add(1)
+		go func(f string) {
+			defer wg.Done()
+			r, err := os.Open(f)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer r.Close()
+			if err := many.CountReader(f, r); err != nil {
+				t.Error(err)
+			}
+		}(f)
+	}
+	wg.Wait()
+	if got, want := many.Results(), one.Results(); !reflect.DeepEqual(got, want) {
+		t.Errorf("concurrent counts differ:\n%v\nwant\n%v", got, want)
+	}
+
+	// run-tests has no extension and goes by its shebang.
+	if n := one.Results()["Python"].FileCount; n != python+1 {
+		t.Errorf("got %d Python files, want %d", n, python+1)
+	}
+}
+
+func TestTypeScriptOnce(t *testing.T) {
+	c := NewCounter()
+	for _, f := range []string{"a.ts", "b.ts", "lib/c.ts"} {
+		if err := c.CountReader(f, strings.NewReader("let x = 1;\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := c.Results()
+	if len(r) != 1 || r["TypeScript"].FileCount != 3 || r["TypeScript"].CodeLines != 3 {
+		t.Errorf("got %v, want one TypeScript row of 3 files", r)
+	}
+}
+
+func TestSingleLanguage(t *testing.T) {
+	for _, split := range []bool{true, false} {
+		c := NewCounter()
+		if !split {
+			c.Languages = Rename(Languages, map[string]string{"GoTest": "Go"})
+		}
+		for _, f := range []string{"hello.go", "hello_test.go"} {
+			if err := c.CountFile(filepath.Join("testdata", f)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r := c.Results()
+		files, lines := 0, 0
+		for _, s := range r {
+			files += s.FileCount
+			lines += s.TotalLines
+		}
+		// The two files hold 21 lines between them.
+		if files != 2 || lines != 21 {
+			t.Errorf("split %v: got %d files and %d lines in %v, want 2 and 21", split, files, lines, r)
+		}
+		if split && (r["Go"].FileCount != 1 || r["GoTest"].FileCount != 1) {
+			t.Errorf("got %v, want one Go and one GoTest file", r)
+		}
+	}
+}
+
+func TestEncodings(t *testing.T) {
+	count := func(dir string) map[string]Stats {
+		c := NewCounter()
+		for _, f := range []string{"Program.cs", "schema.sql", "greet.js"} {
+			if err := c.CountFile(filepath.Join("testdata", "encodings", dir, f)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return c.Results()
+	}
+	want := count("utf8")
+	if len(want) != 3 {
+		t.Fatalf("got %v, want three languages", want)
+	}
+	for _, dir := range []string{"utf8bom", "utf16le", "utf16be", "utf16le-nobom", "utf16be-nobom"} {
+		if got := count(dir); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", dir, got, want)
+		}
+	}
+}
+
+func TestLineEndings(t *testing.T) {
+	files := []string{"hello.c", "queries.py", "brackets.lua", "inventory.pm"}
+	count := func(dir string, reader func(io.Reader) io.Reader) map[string]Stats {
+		c := NewCounter()
+		for _, f := range files {
+			name := filepath.Join("testdata", "endings", dir, f)
+			b, err := ioutil.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.CountReader(name, reader(bytes.NewReader(b))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return c.Results()
+	}
+	whole := func(r io.Reader) io.Reader { return r }
+	want := count("lf", whole)
+	if len(want) != len(files) {
+		t.Fatalf("got %v, want %d languages", want, len(files))
+	}
+	// The mixed files never end a line in a lone "\r" before an empty
+	// line ending in "\n", which would read as one "\r\n".
+	for _, dir := range []string{"crlf", "cr", "mixed"} {
+		if got := count(dir, whole); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", dir, got, want)
+		}
+		// A "\r\n" split between reads is still one line end.
+		if got := count(dir, iotest.OneByteReader); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s read a byte at a time: got %v, want %v", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package sloc
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// decode returns a reader of the content of br as UTF-8. A UTF-8 byte
+// order mark is dropped, and UTF-16 is converted, whether it starts with a
+// byte order mark or just looks like UTF-16 by the NULs of its ASCII
+// characters.
+func decode(br *bufio.Reader) *bufio.Reader {
+	head, _ := br.Peek(detectSize)
+	switch {
+	case bytes.HasPrefix(head, utf8BOM):
+		br.Discard(len(utf8BOM))
+		return br
+	case bytes.HasPrefix(head, []byte{0xff, 0xfe}):
+		br.Discard(2)
+		return bufio.NewReaderSize(&utf16Reader{r: br, next: -1}, detectSize)
+	case bytes.HasPrefix(head, []byte{0xfe, 0xff}):
+		br.Discard(2)
+		return bufio.NewReaderSize(&utf16Reader{r: br, big: true, next: -1}, detectSize)
+	}
+	if big, ok := guessUTF16(head); ok {
+		return bufio.NewReaderSize(&utf16Reader{r: br, big: big, next: -1}, detectSize)
+	}
+	return br
+}
+
+// guessUTF16 reports whether head looks like UTF-16 without a byte order
+// mark, and if so whether it is big-endian: text that is mostly ASCII has
+// a NUL in nearly every other byte.
+func guessUTF16(head []byte) (big, ok bool) {
+	pairs := len(head) / 2
+	if pairs < 2 {
+		return false, false
+	}
+	var even, odd int
+	for i := 0; i+1 < len(head); i += 2 {
+		if head[i] == 0 {
+			even++
+		}
+		if head[i+1] == 0 {
+			odd++
+		}
+	}
+	switch {
+	case odd*10 > pairs*4 && even*10 < pairs:
+		return false, true
+	case even*10 > pairs*4 && odd*10 < pairs:
+		return true, true
+	}
+	return false, false
+}
+
+// A utf16Reader converts UTF-16 read from r to UTF-8.
+type utf16Reader struct {
+	r       *bufio.Reader
+	big     bool
+	next    rune   // a code unit read ahead, or -1
+	pending []byte // the part of a rune that did not fit the last Read
+	b       [2]byte
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	n := copy(p, u.pending)
+	u.pending = u.pending[n:]
+	var b [utf8.UTFMax]byte
+	for n < len(p) {
+		r, err := u.rune()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+		m := utf8.EncodeRune(b[:], r)
+		c := copy(p[n:], b[:m])
+		n += c
+		if c < m {
+			u.pending = append(u.pending[:0], b[c:m]...)
+		}
+	}
+	return n, nil
+}
+
+func (u *utf16Reader) unit() (rune, error) {
+	if r := u.next; r >= 0 {
+		u.next = -1
+		return r, nil
+	}
+	if _, err := io.ReadFull(u.r, u.b[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return 0, err
+	}
+	if u.big {
+		return rune(u.b[0])<<8 | rune(u.b[1]), nil
+	}
+	return rune(u.b[1])<<8 | rune(u.b[0]), nil
+}
+
+func (u *utf16Reader) rune() (rune, error) {
+	r, err := u.unit()
+	if err != nil || !utf16.IsSurrogate(r) {
+		return r, err
+	}
+	r2, err := u.unit()
+	if err != nil {
+		return utf8.RuneError, nil
+	}
+	if d := utf16.DecodeRune(r, r2); d != utf8.RuneError {
+		return d, nil
+	}
+	// Not a pair after all; the second unit stands on its own.
+	u.next = r2
+	return utf8.RuneError, nil
+}
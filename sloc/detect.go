@@ -0,0 +1,176 @@
+package sloc
+
+import (
+	"bytes"
+)
+
+// A Detector chooses between languages sharing an extension by looking at
+// the start of a file. It returns the name of the language, or "" if it
+// cannot tell.
+type Detector func(head []byte) string
+
+// detectSize is how much of a file a Detector gets to see.
+const detectSize = 8192
+
+// Detectors holds the default Detector for each ambiguous extension.
+var Detectors = map[string]Detector{
+	".m":    detectM,
+	".cls":  detectCls,
+	".cl":   detectCl,
+	".v":    detectV,
+	".pl":   detectPl,
+	".pro":  detectPro,
+	".pp":   detectPp,
+	".yml":  detectAnsible,
+	".yaml": detectAnsible,
+	".s":    detectAsm,
+	".S":    detectAsm,
+}
+
+// DetectAs returns a Detector that always picks the language name.
+func DetectAs(name string) Detector {
+	return func([]byte) string { return name }
+}
+
+// lineScore weighs the lines of head by the keywords they start with and
+// returns the name with the highest score.
+func lineScore(head []byte, signals map[string][]string) string {
+	scores := map[string]int{}
+	for _, line := range bytes.FieldsFunc(head, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		line = bytes.TrimSpace(line)
+		for name, prefixes := range signals {
+			for _, p := range prefixes {
+				if bytes.HasPrefix(line, []byte(p)) {
+					scores[name]++
+				}
+			}
+		}
+	}
+	best, score := "", 0
+	for name, n := range scores {
+		if n > score || (n == score && name < best) {
+			best, score = name, n
+		}
+	}
+	return best
+}
+
+func detectM(head []byte) string {
+	return lineScore(head, map[string][]string{
+		"Objective-C": {"#import", "#include", "@interface", "@implementation", "@property", "@protocol", "@end", "#pragma mark", "- (", "+ ("},
+		"MATLAB":      {"function ", "%", "end", "disp(", "fprintf("},
+		"Mathematica": {"(*", "BeginPackage[", "EndPackage[", "Begin[", "End[", "Needs[", "Get["},
+	})
+}
+
+func detectCls(head []byte) string {
+	return lineScore(head, map[string][]string{
+		"LaTeX":        {"\\", "%"},
+		"Apex":         {"public ", "private ", "global ", "@isTest", "@IsTest", "//", "/*", "}"},
+		"Visual Basic": {"VERSION ", "BEGIN", "END", "Attribute ", "Option ", "Private ", "Public ", "'"},
+	})
+}
+
+func detectCl(head []byte) string {
+	return lineScore(head, map[string][]string{
+		"Lisp":   {"(", ";"},
+		"OpenCL": {"#", "//", "/*", "__kernel", "kernel ", "typedef ", "void ", "float", "int ", "}"},
+	})
+}
+
+func detectV(head []byte) string {
+	return lineScore(head, map[string][]string{
+		"Coq":     {"Require ", "Import ", "From ", "Definition ", "Inductive ", "Fixpoint ", "Theorem ", "Lemma ", "Proof.", "Qed.", "(*"},
+		"V":       {"fn ", "pub fn ", "import ", "struct ", "mut ", "println(", "//"},
+		"Verilog": {"endmodule", "`timescale", "`include", "`define", "input ", "output ", "wire ", "reg ", "always ", "assign "},
+	})
+}
+
+func detectAsm(head []byte) string {
+	return lineScore(head, map[string][]string{
+		"Assembly":     {"# ", "movl ", "movq ", "pushq ", "popq ", "leaq ", "addq ", "subq ", "call ", ".type", ".intel_syntax"},
+		"ARM Assembly": {"@", "//", ".arm", ".thumb", ".syntax", "ldr ", "str ", "bx ", "bl ", "push {", "pop {", "stp ", "ldp "},
+	})
+}
+
+func detectPl(head []byte) string {
+	return lineScore(head, map[string][]string{
+		"Perl":   {"use strict", "use warnings", "my ", "our ", "local ", "print ", "package ", "__END__", "=cut"},
+		"Raku":   {"use v6", "unit ", "multi ", "proto ", "sub MAIN", "=begin pod", "#!/usr/bin/env raku", "#!/usr/bin/env perl6"},
+		"Prolog": {":-", "%", "/*", "?-"},
+	})
+}
+
+func detectPro(head []byte) string {
+	return lineScore(head, map[string][]string{
+		"Prolog": {":-", "%", "/*", "?-"},
+		"QMake":  {"#", "QT", "TEMPLATE", "TARGET", "CONFIG", "SOURCES", "HEADERS", "FORMS", "INCLUDEPATH", "include(", "unix", "win32"},
+	})
+}
+
+func detectPp(head []byte) string {
+	return lineScore(head, map[string][]string{
+		"Pascal": {"program ", "unit ", "uses ", "interface", "implementation", "begin", "end.", "var", "const", "type", "procedure ", "function ", "{$", "(*"},
+		"Puppet": {"class ", "node ", "define ", "include ", "#", "$", "package {", "file {", "service {", "exec {", "user {", "ensure ", "}"},
+	})
+}
+
+// detectAnsible picks Ansible for playbooks and leaves other YAML to the
+// table. A playbook is a top-level sequence of plays, each a mapping with
+// hosts and tasks, roles or handlers, or of imports of other playbooks. A
+// hosts key elsewhere, as in a Kubernetes Ingress, does not count.
+func detectAnsible(head []byte) string {
+	seq, mapping := false, false // what the document is, once known
+	hosts, work := false, false  // the keys of the play read so far
+	for _, line := range bytes.Split(head, []byte("\n")) {
+		line = bytes.TrimRight(line, " \t\r")
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		var key []byte
+		switch {
+		case bytes.Equal(line, []byte("---")):
+			seq, mapping = false, false
+			continue
+		case bytes.HasPrefix(line, []byte("- ")) && !mapping:
+			seq, hosts, work = true, false, false
+			key = bytes.TrimLeft(line[2:], " ")
+		case line[0] != ' ' && !seq:
+			mapping = true // such as a Kubernetes manifest
+			continue
+		case seq && bytes.HasPrefix(line, []byte("  ")) && line[2] != ' ':
+			key = line[2:] // a key of the item, aligned after its "- "
+		default:
+			continue
+		}
+		switch {
+		case bytes.HasPrefix(key, []byte("import_playbook:")) && line[0] == '-':
+			return "Ansible"
+		case bytes.HasPrefix(key, []byte("hosts:")):
+			hosts = true
+		case bytes.HasPrefix(key, []byte("tasks:")), bytes.HasPrefix(key, []byte("roles:")), bytes.HasPrefix(key, []byte("handlers:")):
+			work = true
+		}
+		if hosts && work {
+			return "Ansible"
+		}
+	}
+	return ""
+}
+
+// isBinary reports whether head, the start of a file, looks like binary
+// data rather than text: it holds a NUL byte, or many control characters.
+// Bytes above ASCII count as text, as they appear in UTF-8 and the
+// various 8-bit encodings.
+func isBinary(head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+	n := 0
+	for _, b := range head {
+		if b == 0x7f || (b < ' ' && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != '\b' && b != 0x1b) {
+			n++
+		}
+	}
+	return n*10 > len(head)*3
+}
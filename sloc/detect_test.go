@@ -0,0 +1,130 @@
+package sloc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectors(t *testing.T) {
+	tests := []struct {
+		ext  string
+		head string
+		want string
+	}{
+		{".m", "#import <Foundation/Foundation.h>\n@interface Foo : NSObject\n@end\n", "Objective-C"},
+		{".m", "function y = f(x)\n  % doubles\n  y = 2*x;\nend\n", "MATLAB"},
+		{".m", "%{\nA script.\n%}\ndisp(42)\n", "MATLAB"},
+		{".m", "- (void)run;\n+ (id)shared;\n% not MATLAB\n", "Objective-C"},
+		{".m", "% a\n#import <a.h>\n", "MATLAB"}, // a tie goes to the name first in order
+		{".m", "(* ::Package:: *)\nBeginPackage[\"A`\"]\n", "Mathematica"},
+		{".m", "Needs[\"A`\"]\nf[x_] := x^2\n", "Mathematica"},
+		{".m", "x = 1\n", ""},
+		{".cl", "(defun f (x)\n  x)\n", "Lisp"},
+		{".cl", ";; A comment.\n(in-package :a)\n", "Lisp"},
+		{".cl", "__kernel void f(__global float *a)\n{\n}\n", "OpenCL"},
+		{".cl", "#define N 4\n// A kernel.\nkernel void f() {}\n", "OpenCL"},
+		{".v", "module main\n\nfn main() {\n\tprintln('hi')\n}\n", "V"},
+		{".v", "import os\npub fn f() {}\n", "V"},
+		{".v", "`timescale 1ns / 1ps\nmodule m(input a, output y);\n  assign y = a;\nendmodule\n", "Verilog"},
+		{".v", "module m;\n  reg r;\n  wire w;\nendmodule\n", "Verilog"},
+		{".v", "(* A proof. *)\nRequire Import Arith.\nLemma l : 1 = 1.\nProof. reflexivity. Qed.\n", "Coq"},
+		{".v", "Inductive t := A | B.\nDefinition f (x : t) := x.\n", "Coq"},
+		{".s", "# A comment.\n\tmovq $1, %rax\n\tcall f\n", "Assembly"},
+		{".s", "@ A comment.\n\t.syntax unified\n\tldr r0, =1\n\tbx lr\n", "ARM Assembly"},
+		{".S", "#include <a.h>\n\t.intel_syntax noprefix\n\tpushq rbp\n", "Assembly"},
+		{".S", "// A comment.\n\tstp x29, x30, [sp, #-16]!\n\tbl f\n", "ARM Assembly"},
+		{".pl", "use strict;\nuse warnings;\nmy $x = 1;\n", "Perl"},
+		{".pl", "% Facts.\n:- module(m, []).\nf(a).\n", "Prolog"},
+		{".pl", "?- member(X, [1, 2]).\n", "Prolog"},
+		{".pl", "use v6;\nsub MAIN() { say 1 }\n", "Raku"},
+		{".pro", "# A project.\nQT += widgets\nTEMPLATE = app\nSOURCES += a.cpp\n", "QMake"},
+		{".pro", ":- module(m, []).\n% A fact.\nf(a).\n", "Prolog"},
+		{".pp", "program Hello;\nuses SysUtils;\nbegin\nend.\n", "Pascal"},
+		{".pp", "unit Shapes;\ninterface\nimplementation\nend.\n", "Pascal"},
+		{".pp", "# A node.\nnode 'web' {\n  include nginx\n}\n", "Puppet"},
+		{".pp", "class nginx {\n  package { 'nginx':\n    ensure => installed,\n  }\n}\n", "Puppet"},
+		{".yml", "---\n- hosts: web\n  tasks:\n    - ping:\n", "Ansible"},
+		{".yml", "- name: Site\n  hosts: all\n  roles:\n    - web\n", "Ansible"},
+		{".yml", "- import_playbook: web.yml\n", "Ansible"},
+		{".yaml", "- hosts: web\n  vars:\n    a: 1\n", ""},
+		{".yaml", "kind: Ingress\nspec:\n  tls:\n    - hosts:\n        - a\n  tasks: none\n", ""},
+		{".yml", "name: CI\non: push\njobs:\n  a:\n    steps:\n      - run: make\n", ""},
+		{".cls", "\\NeedsTeXFormat{LaTeX2e}\n\\ProvidesClass{a}\n", "LaTeX"},
+		{".cls", "% A class.\n\\LoadClass{report}\n", "LaTeX"},
+		{".cls", "/** An invoice. */\npublic class Invoice {\n    private Decimal total;\n}\n", "Apex"},
+		{".cls", "@isTest\nprivate class InvoiceTest {\n}\n", "Apex"},
+		{".cls", "VERSION 1.0 CLASS\nBEGIN\nEND\nAttribute VB_Name = \"A\"\n", "Visual Basic"},
+		{".cls", "' A class.\nOption Explicit\nPrivate x As Long\n", "Visual Basic"},
+	}
+	for _, tt := range tests {
+		d, ok := Detectors[tt.ext]
+		if !ok {
+			t.Errorf("no detector for %s", tt.ext)
+			continue
+		}
+		if got := d([]byte(tt.head)); got != tt.want {
+			t.Errorf("%s detector on %q: got %q, want %q", tt.ext, tt.head, got, tt.want)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		detectors map[string]Detector
+		want      string
+	}{
+		{"detected", "a.m", "function f()\nend\n", nil, "MATLAB"},
+		{"detected later in the table", "a.m", "#import <a.h>\n@end\n", nil, "Objective-C"},
+		{"undecided goes to the first", "a.m", "x = 1\n", nil, "MATLAB"},
+		{"no detector", "a.m", "#import <a.h>\n", map[string]Detector{}, "MATLAB"},
+		{"forced", "a.m", "function f()\nend\n", map[string]Detector{".m": DetectAs("Objective-C")}, "Objective-C"},
+		{"forced to no match", "a.m", "#import <a.h>\n", map[string]Detector{".m": DetectAs("Go")}, "MATLAB"},
+	}
+	for _, tt := range tests {
+		c := NewCounter()
+		if tt.detectors != nil {
+			c.Detectors = tt.detectors
+		}
+		if err := c.CountReader(tt.file, strings.NewReader(tt.content)); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if r := c.Results(); r[tt.want].FileCount != 1 {
+			t.Errorf("%s: %s counted as %v, want %s", tt.name, tt.file, r, tt.want)
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		binary  bool
+	}{
+		{"text", "package main\n\nfunc main() {}\n", false},
+		{"UTF-8", "// héllo, wörld\nx := \"日本\"\n", false},
+		{"escapes", "\x1b[1mbold\x1b[0m\f\n", false},
+		{"NUL", "select 1;\x00\x00\n", true},
+		{"control characters", "\x01\x02\x03\x04abc\x05\x06", true},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		for _, countBinary := range []bool{false, true} {
+			c := NewCounter()
+			c.CountBinary = countBinary
+			if err := c.CountReader("a.sql", strings.NewReader(tt.content)); err != nil {
+				t.Fatal(err)
+			}
+			skipped := tt.binary && !countBinary
+			if got := c.Results()["SQL"].FileCount == 0; got != skipped {
+				t.Errorf("%s, CountBinary %v: skipped %v, want %v", tt.name, countBinary, got, skipped)
+			}
+			if n := c.SkippedBinary(); (n == 1) != skipped {
+				t.Errorf("%s, CountBinary %v: SkippedBinary is %d", tt.name, countBinary, n)
+			}
+		}
+	}
+}
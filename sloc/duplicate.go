@@ -0,0 +1,121 @@
+package sloc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"sort"
+)
+
+// A Duplicate is a group of files with the same content, but for their
+// line ends.
+type Duplicate struct {
+	Paths []string // sorted
+	Stats          // those of each copy
+
+	first string      // the least of the paths, whose copy is the one counted
+	files []FileStats // what first counts as, held back by SkipDuplicates
+}
+
+// Lines returns the lines of all the copies together.
+func (d *Duplicate) Lines() int { return d.TotalLines * len(d.Paths) }
+
+// A lineEndHash hashes what is written to it with its line ends made
+// "\n", so that CRLF copies of a file hash the same.
+type lineEndHash struct {
+	h  hash.Hash
+	cr bool // whether the last byte written was a "\r"
+}
+
+func newLineEndHash() *lineEndHash { return &lineEndHash{h: sha256.New()} }
+
+func (l *lineEndHash) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if l.cr && p[0] == '\n' {
+			p = p[1:]
+		}
+		l.cr = false
+		i := bytes.IndexByte(p, '\r')
+		if i < 0 {
+			l.h.Write(p)
+			break
+		}
+		l.h.Write(p[:i])
+		l.h.Write([]byte{'\n'})
+		l.cr, p = true, p[i+1:]
+	}
+	return n, nil
+}
+
+func (l *lineEndHash) sum() [sha256.Size]byte {
+	var s [sha256.Size]byte
+	copy(s[:], l.h.Sum(nil))
+	return s
+}
+
+// duplicate records the file name, whose content hashes to sum and counts
+// as files. It reports whether the counter is to hold the files back, and
+// the path of a file found before with the same content, if any. With
+// SkipDuplicates, only the copy with the least path counts, which is
+// known once all the files are in, whatever order they came in.
+func (c *Counter) duplicate(name string, sum [sha256.Size]byte, files []FileStats) (hold bool, of string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dups == nil {
+		c.dups = map[[sha256.Size]byte]*Duplicate{}
+	}
+	d, ok := c.dups[sum]
+	if !ok {
+		d = &Duplicate{}
+		c.dups[sum] = d
+	} else {
+		of = d.first
+	}
+	if !ok || name < d.first {
+		d.Stats = Stats{}
+		for i := range files {
+			d.Add(&files[i].Stats)
+		}
+		d.first = name
+		if c.SkipDuplicates {
+			d.files = files
+		}
+	}
+	d.Paths = append(d.Paths, name)
+	return c.SkipDuplicates, of
+}
+
+// Duplicates returns the groups of files with the same content found when
+// FindDuplicates or SkipDuplicates is set, those with the most lines
+// first. All the empty files make one group.
+func (c *Counter) Duplicates() []Duplicate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var dups []Duplicate
+	for _, d := range c.dups {
+		if len(d.Paths) > 1 {
+			g := Duplicate{Paths: append([]string(nil), d.Paths...)}
+			g.Add(&d.Stats)
+			sort.Strings(g.Paths)
+			dups = append(dups, g)
+		}
+	}
+	sort.Sort(byLines(dups))
+	return dups
+}
+
+type byLines []Duplicate
+
+func (d byLines) Len() int { return len(d) }
+
+func (d byLines) Less(i, j int) bool {
+	if a, b := d[i].Lines(), d[j].Lines(); a != b {
+		return a > b
+	}
+	return d[i].Paths[0] < d[j].Paths[0]
+}
+
+func (d byLines) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
@@ -0,0 +1,95 @@
+package sloc
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDuplicates(t *testing.T) {
+	files := []struct{ name, content string }{
+		{"b/util.go", "package util\n\n// F does it.\nfunc F() {}\n"},
+		{"a/util.go", "package util\r\n\r\n// F does it.\r\nfunc F() {}\r\n"},
+		{"c/util.go", "package util\n\n// F does it.\nfunc F() {}\n"},
+		{"main.go", "package main\n"},
+		{"x.txt.go", ""},
+		{"y.go", ""},
+	}
+	c := NewCounter()
+	c.FindDuplicates = true
+	for _, f := range files {
+		if err := c.CountReader(f.name, strings.NewReader(f.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dups := c.Duplicates()
+	if len(dups) != 2 {
+		t.Fatalf("got %d groups, want 2: %+v", len(dups), dups)
+	}
+	if want := []string{"a/util.go", "b/util.go", "c/util.go"}; !reflect.DeepEqual(dups[0].Paths, want) || dups[0].Lines() != 12 {
+		t.Errorf("got %v with %d lines, want %v with 12", dups[0].Paths, dups[0].Lines(), want)
+	}
+	if want := []string{"x.txt.go", "y.go"}; !reflect.DeepEqual(dups[1].Paths, want) || dups[1].Lines() != 0 {
+		t.Errorf("got %v with %d lines, want the empty files", dups[1].Paths, dups[1].Lines())
+	}
+	if r := c.Results(); r["Go"].FileCount != 6 {
+		t.Errorf("FindDuplicates alone: got %v, want all 6 files counted", r)
+	}
+}
+
+func TestSkipDuplicates(t *testing.T) {
+	// The same content under two languages: which copy counts decides
+	// the language, so it must not depend on the order of counting.
+	files := []string{"vendor/lib/x.js", "src/x.ts", "web/x.js", "main.go"}
+	content := map[string]string{"main.go": "package main\n"}
+	for _, f := range files[:3] {
+		content[f] = "// X.\nexport const x = 1;\n"
+	}
+	orders := [][]int{{0, 1, 2, 3}, {2, 0, 3, 1}, {3, 2, 1, 0}}
+	for _, order := range orders {
+		c := NewCounter()
+		c.SkipDuplicates = true
+		c.KeepFiles = true
+		for _, i := range order {
+			if err := c.CountReader(files[i], strings.NewReader(content[files[i]])); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r := c.Results()
+		if len(r) != 2 || r["TypeScript"].FileCount != 1 || r["TypeScript"].CodeLines != 1 || r["Go"].FileCount != 1 {
+			t.Errorf("order %v: got %v, want x.ts and main.go alone", order, r)
+		}
+		var paths []string
+		for _, f := range c.Files() {
+			paths = append(paths, f.Path)
+		}
+		if want := []string{"main.go", "src/x.ts"}; !reflect.DeepEqual(paths, want) {
+			t.Errorf("order %v: got files %v, want %v", order, paths, want)
+		}
+		if d := c.Duplicates(); len(d) != 1 || len(d[0].Paths) != 3 {
+			t.Errorf("order %v: got groups %+v", order, d)
+		}
+	}
+}
+
+func TestSkipDuplicatesConcurrently(t *testing.T) {
+	for run := 0; run < 20; run++ {
+		c := NewCounter()
+		c.SkipDuplicates = true
+		var wg sync.WaitGroup
+		for _, f := range []string{"d/x.ts", "c/x.js", "b/x.js", "a/x.tsx", "e/x.jsx"} {
+			wg.Add(1)
+			go func(f string) {
+				defer wg.Done()
+				if err := c.CountReader(f, strings.NewReader("const x = 1;\n")); err != nil {
+					t.Error(err)
+				}
+			}(f)
+		}
+		wg.Wait()
+		if r := c.Results(); len(r) != 1 || r["TSX"].FileCount != 1 {
+			t.Fatalf("run %d: got %v, want a/x.tsx alone", run, r)
+		}
+	}
+}
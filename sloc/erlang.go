@@ -0,0 +1,82 @@
+package sloc
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// ErlangTest is the language under which SplitErlangTests counts the test
+// sections of Erlang files.
+const ErlangTest = "ErlangTest"
+
+// An erlangSections tracks whether the lines of an Erlang file are in a
+// -ifdef(TEST). or -ifdef(EUNIT). section, up to its -else. or -endif.
+type erlangSections struct {
+	depth int  // the nesting of conditionals within the section
+	test  bool // whether the lines are test code
+}
+
+// next returns whether line belongs to a test section.
+func (e *erlangSections) next(line []byte) bool {
+	t := bytes.Join(bytes.Fields(line), nil)
+	if e.depth == 0 {
+		if bytes.HasPrefix(t, []byte("-ifdef(TEST)")) || bytes.HasPrefix(t, []byte("-ifdef(EUNIT)")) {
+			e.depth, e.test = 1, true
+		}
+		return e.test
+	}
+	test := e.test
+	switch {
+	case bytes.HasPrefix(t, []byte("-if")):
+		e.depth++
+	case bytes.HasPrefix(t, []byte("-else")) && e.depth == 1:
+		e.test = false
+	case bytes.HasPrefix(t, []byte("-endif")):
+		if e.depth--; e.depth == 0 {
+			e.test = false
+		}
+	}
+	return test
+}
+
+// countErlang counts an Erlang file, with the code of its test sections
+// under ErlangTest. The file counts once, under Erlang unless it is all
+// tests.
+func (c *Counter) countErlang(name string, r io.Reader, l Language) ([]FileStats, error) {
+	f := FileStats{Path: name, Language: l.Name()}
+	t := FileStats{Path: name, Language: ErlangTest}
+	sf, st := l.newScanner(&f.Stats, &c.Options), l.newScanner(&t.Stats, &c.Options)
+
+	var e erlangSections
+	var lastF, lastT []byte // the last lines each scanner saw
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if e.next(line) {
+			st.scan(line)
+			lastT = line
+		} else {
+			sf.scan(line)
+			lastF = line
+		}
+		if err == io.EOF {
+			sf.flush(lastF)
+			st.flush(lastT)
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case t.TotalLines == 0:
+		f.FileCount = 1
+		return []FileStats{f}, nil
+	case f.TotalLines == 0:
+		t.FileCount = 1
+		return []FileStats{t}, nil
+	}
+	f.FileCount = 1
+	return []FileStats{f, t}, nil
+}
@@ -0,0 +1,67 @@
+package sloc
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitErlangTests(t *testing.T) {
+	c := NewCounter()
+	c.SplitErlangTests = true
+	c.KeepFiles = true
+	if err := c.CountFile(filepath.Join("testdata", "queue_srv.erl")); err != nil {
+		t.Fatal(err)
+	}
+	r := c.Results()
+	want := map[string]Stats{
+		"Erlang":   {FileCount: 1, TotalLines: 9, CodeLines: 4, CommentLines: 2, BlankLines: 3},
+		ErlangTest: {FileCount: 0, TotalLines: 7, CodeLines: 5, CommentLines: 1, BlankLines: 1},
+	}
+	for lang, s := range want {
+		got := r[lang]
+		if got.FileCount != s.FileCount || got.TotalLines != s.TotalLines || got.CodeLines != s.CodeLines ||
+			got.CommentLines != s.CommentLines || got.BlankLines != s.BlankLines {
+			t.Errorf("%s: got %+v, want %+v", lang, got, s)
+		}
+	}
+	files := 0
+	for _, s := range r {
+		files += s.FileCount
+	}
+	if files != 1 {
+		t.Errorf("the file counts %d times, want once", files)
+	}
+}
+
+func TestErlangTestOnly(t *testing.T) {
+	c := NewCounter()
+	c.SplitErlangTests = true
+	content := "-ifdef(TEST).\nt() -> ok.\n-endif.\n"
+	if err := c.CountReader("a_tests.erl", strings.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+	r := c.Results()
+	if len(r) != 1 || r[ErlangTest].FileCount != 1 || r[ErlangTest].CodeLines != 3 {
+		t.Errorf("got %v, want one ErlangTest file of 3 lines", r)
+	}
+}
+
+func TestErlangSections(t *testing.T) {
+	content := `-ifdef(TEST).
+-ifdef(DEBUG).
+-endif.
+t() -> ok.
+-else.
+f() -> ok.
+-endif.
+g() -> ok.
+`
+	want := []bool{true, true, true, true, true, false, false, false}
+	var e erlangSections
+	for i, line := range strings.SplitAfter(content, "\n")[:len(want)] {
+		if got := e.next([]byte(line)); got != want[i] {
+			t.Errorf("line %d %q: test is %v, want %v", i+1, line, got, want[i])
+		}
+	}
+}
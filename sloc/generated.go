@@ -0,0 +1,126 @@
+package sloc
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// MinifiedJS is the language under which MinifiedLineLength counts minified
+// JavaScript.
+const MinifiedJS = "JavaScript (minified)"
+
+// generatedLines is how many lines at the start of a file are looked at for a
+// mark of generated code.
+const generatedLines = 5
+
+// generatedName returns the language under which SplitGenerated counts the
+// generated files of lang.
+func generatedName(lang string) string { return lang + " (generated)" }
+
+// isMinified reports whether the JavaScript file name, starting with head,
+// is minified: it is named *.min.js or its lines are longer than max on
+// average.
+func isMinified(name string, head []byte, max int) bool {
+	if strings.HasSuffix(name, ".min.js") {
+		return true
+	}
+	lines := bytes.Count(head, []byte("\n"))
+	if len(head) > 0 && head[len(head)-1] != '\n' {
+		lines++
+	}
+	return lines > 0 && len(head)/lines > max
+}
+
+// isGenerated reports whether the first lines of head mark the file as
+// generated, with a comment starting "Code generated", an @generated tag or,
+// as bundlers write, an /* eslint-disable */ of the whole file on the first
+// line.
+func isGenerated(head []byte) bool {
+	for i, line := range bytes.SplitN(head, []byte("\n"), generatedLines+1) {
+		if i == generatedLines {
+			break
+		}
+		t := bytes.TrimSpace(line)
+		if i == 0 && bytes.Equal(t, []byte("/* eslint-disable */")) {
+			return true
+		}
+		if bytes.Contains(t, []byte("@generated")) {
+			return true
+		}
+		t = bytes.TrimLeft(t, "/#*-;!% \t")
+		if bytes.HasPrefix(t, []byte("Code generated")) {
+			return true
+		}
+	}
+	return false
+}
+
+// goGenerated matches the comment that marks a Go file as generated, as
+// "go help generate" documents it.
+var goGenerated = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// goGeneratedNames are the base names of Go files that are generated even
+// when they lack the comment, as patterns for path.Match.
+var goGeneratedNames = []string{"*.pb.go", "*.pb.gw.go", "zz_generated*.go"}
+
+// isGeneratedGo reports whether the Go file name, starting with head, is
+// generated: it is named like protoc's or Kubernetes' output, or a line
+// before the package clause matches goGenerated.
+func isGeneratedGo(name string, head []byte) bool {
+	base := path.Base(name)
+	for _, p := range goGeneratedNames {
+		if ok, _ := path.Match(p, base); ok {
+			return true
+		}
+	}
+	for _, line := range bytes.Split(head, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if goGenerated.Match(line) {
+			return true
+		}
+		if bytes.HasPrefix(line, []byte("package ")) {
+			break
+		}
+	}
+	return false
+}
+
+// generated reports whether the file name, starting with head, is
+// generated. Go has a convention for this; other languages make do with
+// isGenerated.
+func generated(name string, head []byte) bool {
+	if path.Ext(name) == ".go" {
+		return isGeneratedGo(name, head)
+	}
+	return isGenerated(head)
+}
+
+// variant returns the language to count the file name under: l, or l
+// renamed for minified JavaScript or generated code when the counter tells
+// them apart. It returns false if the file is to be skipped.
+func (c *Counter) variant(name string, l Language, head []byte) (Language, bool) {
+	if c.MinifiedLineLength > 0 && l.Name() == "JavaScript" && isMinified(name, head, c.MinifiedLineLength) {
+		if c.SkipMinified {
+			c.logf("  %s: minified, skipped\n", name)
+			c.mu.Lock()
+			c.minified++
+			c.mu.Unlock()
+			return l, false
+		}
+		l.Namer = MinifiedJS
+		return l, true
+	}
+	if c.SplitGenerated && generated(name, head) {
+		l.Namer = Namer(generatedName(l.Name()))
+	}
+	return l, true
+}
+
+// SkippedMinified returns how many files SkipMinified skipped.
+func (c *Counter) SkippedMinified() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.minified
+}
@@ -0,0 +1,132 @@
+package sloc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMinified(t *testing.T) {
+	tests := []struct {
+		file   string
+		length int
+		want   string
+	}{
+		{"dist/app.min.js", 200, MinifiedJS},
+		{"dist/app.min.js", 0, "JavaScript"},
+		{"dist/bundle.js", 200, MinifiedJS},
+		{"dist/bundle.js", 1000, "JavaScript"},
+		{"dist/vendor.js", 200, "JavaScript"},
+		{"clicks.js", 200, "JavaScript"},
+		{"clicks.js", 1, MinifiedJS},
+		{"shape.ts", 1, "TypeScript"},
+	}
+	for _, tt := range tests {
+		c := NewCounter()
+		c.MinifiedLineLength = tt.length
+		if err := c.CountFile(filepath.Join("testdata", tt.file)); err != nil {
+			t.Fatal(err)
+		}
+		if r := c.Results(); r[tt.want].FileCount != 1 {
+			t.Errorf("%s, MinifiedLineLength %d: counted as %v, want %s", tt.file, tt.length, r, tt.want)
+		}
+	}
+}
+
+func TestSkipMinified(t *testing.T) {
+	c := NewCounter()
+	c.MinifiedLineLength = 200
+	c.SkipMinified = true
+	for _, f := range []string{"dist/app.min.js", "dist/bundle.js", "dist/vendor.js"} {
+		if err := c.CountFile(filepath.Join("testdata", f)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r := c.Results(); len(r) != 1 || r["JavaScript"].FileCount != 1 {
+		t.Errorf("got %v, want vendor.js alone", r)
+	}
+	if n := c.SkippedMinified(); n != 2 {
+		t.Errorf("SkippedMinified is %d, want 2", n)
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want bool
+	}{
+		{"Go", "// Code generated by stringer; DO NOT EDIT.\n\npackage a\n", true},
+		{"after a license", "// Copyright 2020.\n\n// Code generated by protoc-gen-go. DO NOT EDIT.\n", true},
+		{"shell", "#!/bin/sh\n# Code generated by make.\n", true},
+		{"tag", "/**\n * @generated SignedSource<<abc>>\n */\n", true},
+		{"eslint", "/* eslint-disable */\nexport const a = 1;\n", true},
+		{"eslint later", "// A module.\n/* eslint-disable */\n", false},
+		{"eslint one rule", "/* eslint-disable no-console */\n", false},
+		{"too late", "a\nb\nc\nd\ne\n// Code generated by x.\n", false},
+		{"not a mark", "// Code that generated the tables.\n// Code generation is below.\n", false},
+		{"plain", "package a\n", false},
+	}
+	for _, tt := range tests {
+		if got := isGenerated([]byte(tt.head)); got != tt.want {
+			t.Errorf("%s: isGenerated is %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	c := NewCounter()
+	c.SplitGenerated = true
+	for _, f := range []string{"dist/vendor.js", "clicks.js"} {
+		if err := c.CountFile(filepath.Join("testdata", f)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r := c.Results(); r["JavaScript (generated)"].FileCount != 1 || r["JavaScript"].FileCount != 1 {
+		t.Errorf("SplitGenerated: got %v", r)
+	}
+}
+
+func TestGeneratedGo(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		head string
+		want bool
+	}{
+		{"stringer", "kind_string.go", "// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.\n\npackage a\n", true},
+		{"after a license", "a.go", "// Copyright 2020 The Authors.\n\n// Code generated by mockgen. DO NOT EDIT.\n\npackage a\n", true},
+		{"CRLF", "a.go", "// Code generated by x. DO NOT EDIT.\r\npackage a\r\n", true},
+		{"build tag first", "a.go", "//go:build linux\n\n// Code generated by cgo -godefs; DO NOT EDIT.\n\npackage a\n", true},
+		{"no period", "a.go", "// Code generated by x. DO NOT EDIT\npackage a\n", false},
+		{"trailing text", "a.go", "// Code generated by x. DO NOT EDIT. Really.\npackage a\n", false},
+		{"indented", "a.go", " // Code generated by x. DO NOT EDIT.\npackage a\n", false},
+		{"block comment", "a.go", "/* Code generated by x. DO NOT EDIT. */\npackage a\n", false},
+		{"nothing in between", "a.go", "// Code generated  DO NOT EDIT.\npackage a\n", true},
+		{"after the package clause", "a.go", "package a\n\n// Code generated by x. DO NOT EDIT.\n", false},
+		{"protoc", "api/user.pb.go", "package user\n", true},
+		{"grpc-gateway", "user.pb.gw.go", "package user\n", true},
+		{"Kubernetes", "zz_generated.deepcopy.go", "package v1\n", true},
+		{"hand-written", "main.go", "// Package main does it.\npackage main\n", false},
+		{"other marks", "a.go", "// @generated\npackage a\n", false},
+	}
+	for _, tt := range tests {
+		if got := generated(tt.file, []byte(tt.head)); got != tt.want {
+			t.Errorf("%s: generated is %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, split := range []bool{false, true} {
+		c := NewCounter()
+		c.SplitGenerated = split
+		for _, f := range []string{"kind_string.go", "user.pb.go", "hello.go"} {
+			if err := c.CountFile(filepath.Join("testdata", f)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r := c.Results()
+		if split && (r["Go (generated)"].FileCount != 2 || r["Go"].FileCount != 1) {
+			t.Errorf("SplitGenerated: got %v, want 2 generated files and 1 other", r)
+		}
+		if !split && (len(r) != 1 || r["Go"].FileCount != 3) {
+			t.Errorf("not SplitGenerated: got %v, want 3 Go files", r)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package sloc
+
+import (
+	"bytes"
+	"strings"
+)
+
+// A Heredoc describes the here documents of a language: string literals
+// that start on the line after a marker such as <<EOF and run up to a line
+// holding just the word that followed it.
+type Heredoc struct {
+	Start string // the marker, such as "<<"
+
+	// Indents are the characters that may follow Start to let the
+	// closing line be indented, as in <<-EOF and <<~EOF.
+	Indents string
+
+	Space    bool // blanks may come between Start and the word
+	Upper    bool // an unquoted word must start with a capital letter
+	Indented bool // the closing line may always be indented
+	Trailing bool // the closing word may be followed by punctuation
+}
+
+var (
+	shHeredoc   = Heredoc{Start: "<<", Indents: "-", Space: true}
+	rubyHeredoc = Heredoc{Start: "<<", Indents: "-~", Upper: true}
+	perlHeredoc = Heredoc{Start: "<<", Indents: "~"}
+	phpHeredoc  = Heredoc{Start: "<<<", Space: true, Indented: true, Trailing: true}
+	hclHeredoc  = Heredoc{Start: "<<", Indents: "-"}
+	crHeredoc   = Heredoc{Start: "<<-", Indented: true}
+)
+
+// A heredocEnd is what closes a here document.
+type heredocEnd struct {
+	word     string
+	indented bool
+	trailing bool
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// open recognizes a here document marker at the start of c, returning its
+// length and what closes the document, or zero if there is none.
+func (h *Heredoc) open(c []byte) (int, heredocEnd) {
+	if !hasMarker(c, h.Start) {
+		return 0, heredocEnd{}
+	}
+	i := len(h.Start)
+	if i < len(c) && c[i] == h.Start[len(h.Start)-1] {
+		// Something else, such as a shell here string <<<.
+		return 0, heredocEnd{}
+	}
+	e := heredocEnd{indented: h.Indented, trailing: h.Trailing}
+	modified := false
+	if i < len(c) && h.Indents != "" && strings.IndexByte(h.Indents, c[i]) >= 0 {
+		e.indented, modified = true, true
+		i++
+	}
+	if h.Space {
+		for i < len(c) && (c[i] == ' ' || c[i] == '\t') {
+			i++
+		}
+	}
+	if i < len(c) && (c[i] == '"' || c[i] == '\'') {
+		j := bytes.IndexByte(c[i+1:lineLen(c)], c[i])
+		if j <= 0 {
+			return 0, heredocEnd{}
+		}
+		e.word = string(c[i+1 : i+1+j])
+		return i + j + 2, e
+	}
+	if h.Space && i < len(c) && c[i] == '\\' {
+		i++
+	}
+	j := i
+	for j < len(c) && isWordByte(c[j]) {
+		j++
+	}
+	if j == i || c[i] <= '9' || (h.Upper && !modified && !('A' <= c[i] && c[i] <= 'Z')) {
+		return 0, heredocEnd{}
+	}
+	e.word = string(c[i:j])
+	return j, e
+}
+
+// closes reports whether line is the last line of the here document.
+func (e *heredocEnd) closes(line []byte) bool {
+	if e.indented {
+		line = bytes.TrimLeft(line, " \t")
+	}
+	line = bytes.TrimRight(line, " \t")
+	if !bytes.HasPrefix(line, []byte(e.word)) {
+		return false
+	}
+	rest := line[len(e.word):]
+	return len(rest) == 0 || (e.trailing && !isWordByte(rest[0]))
+}
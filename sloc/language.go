@@ -0,0 +1,633 @@
+package sloc
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"strings"
+)
+
+// Languages is the default table of languages a Counter recognizes.
+var Languages = []Language{
+	{"Thrift", mExt(".thrift"), cComments},
+	{"Protocol Buffers", mExt(".proto"), cComments},
+	{"Cap'n Proto", mExt(".capnp"), shComments},
+	{"FlatBuffers", mExt(".fbs"), cComments},
+	{"Avro IDL", mExt(".avdl"), cComments},
+	{"GraphQL", mExt(".graphql", ".gql"), gqlComments},
+
+	{"C", mExt(".c", ".h"), cComments},
+	{"C++", mExt(".cc", ".cpp", ".cxx", ".hh", ".hpp", ".hxx"), cComments},
+	{"C#", mExt(".cs"), cComments},
+	{"CUDA", mExt(".cu", ".cuh"), cComments},
+	{"OpenCL", mExt(".cl"), cComments},
+	{"Metal", mExt(".metal"), cComments},
+	{"GLSL", mExt(".glsl", ".vert", ".frag", ".geom", ".tesc", ".tese", ".comp"), cComments},
+	{"HLSL", mExt(".hlsl", ".fx"), cComments},
+	{"WGSL", mExt(".wgsl"), wgslComments},
+	{"GoTest", mSuffix("_test.go"), goComments},
+	{"Go", mExt(".go"), goComments},
+
+	{"Rust", mExt(".rs", ".rc"), rsComments},
+	{"Scala", mExt(".scala"), scalaComments},
+	{"Java", mExt(".java"), cComments},
+	{"Apex", mExt(".cls", ".trigger"), cComments},
+	{"Kotlin", mExt(".kt", ".kts"), ktComments},
+	{"Groovy", Matcher{
+		Exts:  []string{".groovy", ".gvy", ".gradle"},
+		Names: []string{"Jenkinsfile"},
+	}, groovyComments},
+	{"Swift", mExt(".swift"), swiftComments},
+	{"D", mExt(".d", ".di"), dComments},
+	{"Vala", mExt(".vala", ".vapi"), valaComments},
+	{"Genie", mExt(".gs"), valaComments},
+	{"Solidity", mExt(".sol"), cComments},
+	{"Pascal", mExt(".pas", ".pp", ".dpr", ".dpk", ".lpr"), pascalComments},
+	{"Puppet", mExt(".pp"), puppetComments},
+	{"Modula-2", Matcher{Exts: []string{".mod", ".def"}, Except: []string{"go.mod"}}, modulaComments},
+	{"Oberon", mExt(".ob2", ".obn"), modulaComments},
+	{"Ada", mExt(".ads", ".adb", ".ada"), adaComments},
+	{"Eiffel", mExt(".e"), eiffelComments},
+	{"Zig", mExt(".zig"), zigComments},
+	{"V", mExt(".v"), vComments},
+
+	{"YACC", mExt(".y"), cComments},
+	{"Lex", mExt(".l"), cComments},
+
+	{"Verilog", mExt(".v"), svComments},
+	{"SystemVerilog", mExt(".sv", ".svh"), svComments},
+	{"VHDL", mExt(".vhd", ".vhdl"), vhdlComments},
+
+	{"Lua", mExt(".lua"), luaComments},
+
+	{"SQL", mExt(".sql"), sqlComments},
+
+	{"Haskell", mExt(".hs"), hsComments},
+	{"Haskell", mExt(".lhs"), lhsComments},
+	{"ML", mExt(".ml", ".mli"), mlComments},
+	{"Coq", mExt(".v"), coqComments},
+	{"F#", mExt(".fs", ".fsx", ".fsi"), fsComments},
+	{"Elm", mExt(".elm"), elmComments},
+	{"PureScript", mExt(".purs"), elmComments},
+	{"Reason", mExt(".re", ".rei"), cComments},
+	{"ReScript", mExt(".res", ".resi"), jsComments},
+
+	{"Perl", mExt(".pl", ".pm"), perlComments},
+	{"Raku", mExt(".raku", ".pl", ".rakumod", ".rakutest", ".p6", ".pl6", ".pm6"), rakuComments},
+	{"Prolog", mExt(".pl", ".pro", ".prolog"), prologComments},
+	{"PHP", mExt(".php"), phpComments},
+	{"ColdFusion", mExt(".cfm"), cfmComments},
+	{"ColdFusion", mExt(".cfc"), cfcComments},
+
+	{"Shell", mExt(".sh"), bashComments},
+	{"Bash", Matcher{
+		Exts:  []string{".bash"},
+		Names: []string{".bashrc", ".bash_profile", ".bash_login", ".bash_logout", ".bash_aliases", ".profile"},
+	}, bashComments},
+	{"Zsh", Matcher{
+		Exts:  []string{".zsh"},
+		Names: []string{".zshrc", ".zshenv", ".zprofile", ".zlogin", ".zlogout"},
+	}, bashComments},
+	{"C Shell", Matcher{
+		Exts:  []string{".csh", ".tcsh"},
+		Names: []string{".cshrc", ".tcshrc", ".login", ".logout"},
+	}, shComments},
+	{"Fish", mExt(".fish"), fishComments},
+	{"R", mExt(".r", ".R"), rComments},
+	{"Maple", mExt(".mpl"), hashComments},
+	{"SAS", mExt(".sas"), sasComments},
+	{"Stata", mExt(".do", ".ado"), stataComments},
+	{"R Markdown", mExt(".Rmd", ".rmd"), rmdComments},
+	{"Sweave", mExt(".Rnw", ".rnw"), rnwComments},
+	{"Tcl", mExt(".tcl"), shComments},
+
+	{"MATLAB", mExt(".m"), matlabComments},
+	{"Mathematica", mExt(".m", ".wl", ".wls"), mmaComments},
+	{"Objective-C", mExt(".m"), cComments},
+	{"Objective-C++", mExt(".mm"), cComments},
+
+	{"Ruby", mExt(".rb"), rubyComments},
+	{"Smalltalk", mExt(".st"), stComments},
+	{"Crystal", mExt(".cr"), crComments},
+	{"Python", mExt(".py"), pyComments},
+	{"Julia", mExt(".jl"), jlComments},
+	{"Nim", mExt(".nim", ".nims"), nimComments},
+	{"Assembly", mExt(".asm"), semiComments},
+	{"Assembly", mExt(".s"), gasComments},
+	{"Assembly", mExt(".S"), gasCppComments},
+	{"ARM Assembly", mExt(".s"), armComments},
+	{"ARM Assembly", mExt(".S"), armCppComments},
+	{"LLVM IR", mExt(".ll"), llComments},
+	{"WebAssembly", mExt(".wat", ".wast"), watComments},
+	{"TableGen", mExt(".td"), tdComments},
+	{"Lisp", mExt(".lsp", ".lisp", ".cl"), lispComments},
+	{"Emacs Lisp", mExt(".el"), elComments},
+	{"Scheme", mExt(".scm", ".scheme", ".ss"), schemeComments},
+	{"Racket", mExt(".rkt"), schemeComments},
+	{"Clojure", mExt(".clj", ".cljs", ".cljc", ".edn"), cljComments},
+
+	{"Make", mName("makefile", "Makefile", "MAKEFILE"), shComments},
+	{"CMake", mName("CMakeLists.txt"), shComments},
+	{"QMake", mExt(".pro", ".pri"), hashComments},
+	{"Jam", mName("Jamfile", "Jamrules"), shComments},
+	{"Starlark", Matcher{
+		Exts:  []string{".bzl", ".star"},
+		Names: []string{"BUILD", "BUILD.bazel", "WORKSPACE", "WORKSPACE.bazel", "MODULE.bazel"},
+	}, pyComments},
+
+	{"Markdown", mExt(".md"), noComments},
+	{"LaTeX", mExt(".tex", ".sty", ".cls"), texComments},
+	{"reStructuredText", mExt(".rst"), rstComments},
+	{"AsciiDoc", mExt(".adoc", ".asciidoc"), adocComments},
+	{"Org", mExt(".org"), orgComments},
+
+	{"Nginx", mName("nginx.conf"), shComments},
+	{"Apache", mName("httpd.conf", "apache2.conf", ".htaccess"), leadShComments},
+	{"Caddyfile", mName("Caddyfile"), shComments},
+	{"HAProxy", mName("haproxy.cfg"), shComments},
+	{"Ansible", Matcher{Paths: []string{
+		"roles/*/tasks/*.yml", "roles/*/tasks/*.yaml",
+		"roles/*/handlers/*.yml", "roles/*/handlers/*.yaml",
+		"playbooks/*.yml", "playbooks/*.yaml",
+	}}, yamlComments},
+	{"YAML", mExt(".yml", ".yaml"), yamlComments},
+	{"Ansible", mExt(".yml", ".yaml"), yamlComments},
+	{"TOML", mExt(".toml"), tomlComments},
+	{"INI", mExt(".ini", ".cfg"), iniComments},
+	{"Editor/VCS config", mName(".editorconfig", ".gitattributes", ".gitconfig", ".gitmodules"), iniComments},
+	{"Properties", mExt(".properties"), propComments},
+	{"JSON", mExt(".json"), noComments},
+	{"JSONC", mExt(".jsonc"), jsComments},
+
+	{"HAML", mExt(".haml"), hamlComments},
+	{"Pug", mExt(".pug", ".jade"), pugComments},
+	{"Slim", mExt(".slim"), slimComments},
+	{"SASS", mExt(".sass"), scssComments},
+	{"SCSS", mExt(".scss"), scssComments},
+	{"Less", mExt(".less"), scssComments},
+	{"Stylus", mExt(".styl", ".stylus"), scssComments},
+	{"PostCSS", mExt(".pcss", ".postcss"), cssComments},
+
+	{"HTML", mExt(".htm", ".html", ".xhtml"), xmlComments},
+	{"XML", mExt(".xml"), xmlComments},
+	{"CSS", mExt(".css"), cssComments},
+	{"JavaScript", mExt(".js"), jsComments},
+	{"TypeScript (decl)", mSuffix(".d.ts"), jsComments},
+	{"TypeScript", mExt(".ts"), jsComments},
+	{"ActionScript", mExt(".as"), jsComments},
+	{"Haxe", mExt(".hx"), cComments},
+	{"QML", mExt(".qml", ".qbs"), jsComments},
+	{"JSX", mExt(".jsx"), jsComments},
+	{"TSX", mExt(".tsx"), jsComments},
+	{"CoffeeScript", mExt(".coffee"), coffeeComments},
+	{"Jinja", mExt(".j2", ".jinja", ".jinja2"), jinjaComments},
+	{"Twig", mExt(".twig"), jinjaComments},
+	{"ERB", mExt(".erb"), erbComments},
+	{"Handlebars", mExt(".hbs", ".handlebars"), hbsComments},
+	{"Mustache", mExt(".mustache"), hbsComments},
+	{"Liquid", mExt(".liquid"), liquidComments},
+	{"Vue", mExt(".vue"), sfcComments},
+	{"Svelte", mExt(".svelte"), sfcComments},
+
+	{"Erlang", mExt(".erl", ".hrl"), erlangComments},
+	{"Elixir", mExt(".ex", ".exs"), exComments},
+
+	{"Vim script", Matcher{
+		Exts:  []string{".vim"},
+		Names: []string{".vimrc", "vimrc", "_vimrc", ".gvimrc", "gvimrc", ".exrc"},
+	}, vimComments},
+	{"AWK", mExt(".awk"), awkComments},
+	{"sed", mExt(".sed"), leadShComments},
+	{"HCL", mExt(".tf", ".tfvars", ".hcl", ".nomad"), hclComments},
+	{"Nix", mExt(".nix"), nixComments},
+	{"Fortran", mExt(".f90", ".f95", ".f03", ".f08", ".F90"), fortranComments},
+	{"Fortran", mExt(".f", ".for", ".f77", ".F"), fixedFortranComments},
+	{"PowerShell", mExt(".ps1", ".psm1", ".psd1"), psComments},
+	{"Visual Basic", mExt(".vb", ".vbs", ".bas", ".cls", ".frm"), vbComments},
+	{"AppleScript", mExt(".applescript", ".scpt"), asComments},
+	{"AutoHotkey", mExt(".ahk"), ahkComments},
+	{"Batch", mExt(".bat", ".cmd", ".BAT", ".CMD"), batchComments},
+	{"ABAP", mExt(".abap"), abapComments},
+	{"COBOL", mExt(".cbl", ".cob", ".cpy", ".CBL", ".COB", ".CPY"), cobolComments},
+
+	// Last, so that Dockerfile.md, Dockerfile.ps1 and the like go by their
+	// extensions. Keep it last when adding languages.
+	{"Dockerfile", Matcher{
+		Exts:     []string{".dockerfile"},
+		Names:    []string{"Dockerfile", "Containerfile"},
+		Prefixes: []string{"Dockerfile.", "Containerfile."},
+	}, leadShComments},
+}
+
+// LanguageByName returns the language in Languages with the given name.
+func LanguageByName(name string) (Language, bool) {
+	for _, l := range Languages {
+		if l.Name() == name {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
+
+// Categories sorts the languages that are not programming languages, by
+// name, into groups such as "Config".
+var Categories = map[string]string{
+	"YAML":              "Config",
+	"Ansible":           "Config",
+	"TOML":              "Config",
+	"INI":               "Config",
+	"Properties":        "Config",
+	"JSON":              "Config",
+	"JSONC":             "Config",
+	"Editor/VCS config": "Config",
+	"Nginx":             "Config",
+	"Apache":            "Config",
+	"Caddyfile":         "Config",
+	"HAProxy":           "Config",
+
+	"Markdown":         "Docs",
+	"LaTeX":            "Docs",
+	"reStructuredText": "Docs",
+	"AsciiDoc":         "Docs",
+	"Org":              "Docs",
+}
+
+// A Block is a pair of block comment markers. A space in a marker stands
+// for any blanks, as in the tags of templates such as {% comment %}.
+type Block struct {
+	Start   string
+	End     string
+	Nesting bool
+}
+
+var (
+	cLine        = []string{`//`}
+	shLine       = []string{`#`}
+	cBlock       = []Block{{`/*`, `*/`, false}}
+	xmlBlock     = []Block{{`<!--`, `-->`, false}}
+	nestedCBlock = []Block{{`/*`, `*/`, true}}
+	hsBlock      = []Block{{`{-`, `-}`, true}}
+	lispBlock    = []Block{{`#|`, `|#`, true}}
+	cfBlocks     = []Block{{`<!---`, `--->`, true}, xmlBlock[0]}
+	cDoc         = []string{`/**`, `///`, `/*!`, `//!`}
+)
+
+// A Commenter describes the comment syntax of a language. Empty markers
+// are never matched.
+type Commenter struct {
+	LineComments []string
+	Blocks       []Block
+	Strings      []Quote
+	Lines        func(*Options) LineFunc
+	LineStart    bool // block comment markers only count at the start of a line
+	Heredoc      *Heredoc
+	Regions      []Region
+
+	// Operators are code that would otherwise be taken for a comment
+	// marker, such as F#'s (*).
+	Operators []string
+
+	// LeadComments are line comment markers that only count at the start
+	// of a line, whatever their case. Those ending in a letter must be
+	// whole words.
+	LeadComments []string
+
+	// LeadBlocks are block comments that only start at the start of a
+	// line, such as SAS's * ... ; statements.
+	LeadBlocks []Block
+
+	// DocComments are the markers that start documentation comments, such
+	// as Javadoc's /**. More of their last character, or a /, after them
+	// makes an ordinary comment, as in /***/ and ////.
+	DocComments []string
+}
+
+// A Region is a part of a file in another language embedded in the
+// surrounding one, such as the code between <?php and ?> or a <script>
+// element in HTML. Its end is recognized in code and line comments.
+type Region struct {
+	Start, End string
+	Commenter  *Commenter
+}
+
+var (
+	noComments     = Commenter{}
+	xmlComments    = Commenter{Blocks: xmlBlock}
+	cComments      = Commenter{LineComments: cLine, Blocks: cBlock, Strings: cQuotes, DocComments: cDoc}
+	goComments     = Commenter{LineComments: cLine, Blocks: cBlock, Strings: goQuotes}
+	jsComments     = Commenter{LineComments: cLine, Blocks: cBlock, Strings: jsQuotes, DocComments: cDoc}
+	rsComments     = Commenter{LineComments: cLine, Blocks: nestedCBlock, Strings: rsQuotes, DocComments: cDoc}
+	wgslComments   = Commenter{LineComments: cLine, Blocks: nestedCBlock}
+	ktComments     = Commenter{LineComments: cLine, Blocks: nestedCBlock, Strings: ktQuotes, DocComments: cDoc}
+	groovyComments = Commenter{LineComments: cLine, Blocks: cBlock, Strings: groovyQuotes, DocComments: cDoc}
+	zigComments    = Commenter{LineComments: cLine, Strings: zigQuotes}
+	vComments      = Commenter{LineComments: cLine, Blocks: nestedCBlock, Strings: vQuotes}
+	swiftComments  = Commenter{LineComments: cLine, Blocks: nestedCBlock, Strings: swiftQuotes, DocComments: cDoc}
+	dComments      = Commenter{LineComments: cLine, Blocks: []Block{cBlock[0], {`/+`, `+/`, true}}, Strings: dlangQuotes}
+	svComments     = Commenter{LineComments: cLine, Blocks: cBlock, Strings: dqQuotes}
+	vhdlComments   = Commenter{LineComments: []string{`--`}, Blocks: cBlock, Strings: vhdlQuotes}
+	valaComments   = Commenter{LineComments: cLine, Blocks: cBlock, Strings: ktQuotes}
+	scalaComments  = Commenter{LineComments: cLine, Blocks: cBlock, Strings: dqQuotes, DocComments: cDoc}
+	phpCode        = Commenter{LineComments: []string{`//`, `#`}, Blocks: cBlock, Strings: cQuotes, Heredoc: &phpHeredoc}
+	cssComments    = Commenter{Blocks: cBlock, Strings: cQuotes}
+	scssComments   = Commenter{LineComments: cLine, Blocks: cBlock, Strings: scssQuotes}
+	shComments     = Commenter{LineComments: shLine, Strings: shQuotes}
+	rComments      = Commenter{LineComments: shLine, Strings: shQuotes, DocComments: []string{`#'`}}
+	puppetComments = Commenter{LineComments: shLine, Blocks: cBlock, Strings: cQuotes}
+	hashComments   = Commenter{LineComments: shLine, Strings: dqQuotes}
+	fishComments   = Commenter{LineComments: shLine, Strings: cQuotes}
+	awkComments    = Commenter{LineComments: shLine, Strings: awkQuotes}
+	bashComments   = Commenter{LineComments: shLine, Strings: shQuotes, Heredoc: &shHeredoc}
+	semiComments   = Commenter{LineComments: []string{`;`}}
+	llComments     = Commenter{LineComments: []string{`;`}, Strings: dqQuotes}
+	watComments    = Commenter{LineComments: []string{`;;`}, Blocks: []Block{{`(;`, `;)`, true}}, Strings: dqQuotes}
+	tdComments     = Commenter{LineComments: cLine, Blocks: cBlock, Strings: []Quote{{Start: `[{`, End: `}]`, Multiline: true}, dquote}}
+	gasComments    = Commenter{LineComments: shLine, Blocks: cBlock, Strings: dqQuotes}
+	gasCppComments = Commenter{LineComments: shLine, Blocks: cBlock, Strings: dqQuotes, Lines: cppLines}
+	armComments    = Commenter{LineComments: []string{`@`, `//`}, Blocks: cBlock, Strings: dqQuotes, LeadComments: shLine}
+	armCppComments = Commenter{LineComments: []string{`@`, `//`}, Blocks: cBlock, Strings: dqQuotes, LeadComments: shLine, Lines: cppLines}
+	lispComments   = Commenter{LineComments: []string{`;`}, Blocks: lispBlock, Strings: lispQuotes}
+	schemeComments = Commenter{LineComments: []string{`;`}, Blocks: lispBlock, Strings: lispQuotes, Lines: schemeLines}
+	elComments     = Commenter{LineComments: []string{`;`}, Strings: elQuotes}
+	cljComments    = Commenter{LineComments: []string{`;`}, Strings: cljQuotes, Lines: cljLines}
+	hsComments     = Commenter{LineComments: []string{`--`}, Blocks: hsBlock, Strings: dqQuotes, Lines: hsLines}
+	lhsComments    = Commenter{LineComments: []string{`--`}, Blocks: hsBlock, Strings: dqQuotes, Lines: lhsLines}
+	mlComments     = Commenter{Blocks: []Block{{`(*`, `*)`, true}}, Strings: mlQuotes}
+	modulaComments = Commenter{Blocks: []Block{{`(*`, `*)`, true}}, Strings: plainQuotes}
+	coqComments    = Commenter{Blocks: []Block{{`(*`, `*)`, true}}, Strings: plainDQuotes}
+	fsComments     = Commenter{LineComments: cLine, Blocks: []Block{{`(*`, `*)`, true}}, Strings: fsQuotes, Operators: []string{`(*)`}}
+	adaComments    = Commenter{LineComments: []string{`--`}, Strings: vhdlQuotes}
+	eiffelComments = Commenter{LineComments: []string{`--`}, Strings: eiffelQuotes}
+	elmComments    = Commenter{LineComments: []string{`--`}, Blocks: hsBlock, Strings: elmQuotes}
+	sqlComments    = Commenter{LineComments: []string{`--`, `#`}, Blocks: cBlock, Strings: cQuotes}
+	luaComments    = Commenter{LineComments: []string{`--`}, Strings: luaQuotes}
+	pyComments     = Commenter{LineComments: shLine, Strings: pyQuotes}
+	gqlComments    = Commenter{LineComments: shLine, Strings: gqlQuotes}
+	nimComments    = Commenter{LineComments: shLine, Blocks: []Block{{`##[`, `]##`, true}, {`#[`, `]#`, true}}, Strings: nimQuotes, DocComments: []string{`##`}}
+	jlComments     = Commenter{LineComments: shLine, Blocks: []Block{{`#=`, `=#`, true}}, Strings: jlQuotes}
+	mmaComments    = Commenter{Blocks: []Block{{`(*`, `*)`, true}}, Strings: []Quote{{Start: `"`, End: `"`, Escape: '\\', Multiline: true}}}
+	matlabComments = Commenter{LineComments: []string{`%`}, Blocks: []Block{{`%{`, `%}`, false}}, Strings: dqQuotes, LineStart: true}
+	erlangComments = Commenter{LineComments: []string{`%`}, Strings: erlangQuotes}
+	exComments     = Commenter{LineComments: shLine, Strings: exQuotes, Lines: elixirLines}
+	rubyComments   = Commenter{LineComments: shLine, Blocks: []Block{{"=begin", "=end", false}}, Strings: cQuotes, LineStart: true, Heredoc: &rubyHeredoc}
+	crComments     = Commenter{LineComments: shLine, Strings: cQuotes, Heredoc: &crHeredoc}
+	coffeeComments = Commenter{LineComments: shLine, Blocks: []Block{{"###", "###", false}}, Strings: cQuotes, LineStart: true}
+	psComments     = Commenter{LineComments: shLine, Blocks: []Block{{"<#", "#>", false}}, Strings: psQuotes}
+	hclComments    = Commenter{LineComments: []string{`#`, `//`}, Blocks: cBlock, Strings: dqQuotes, Heredoc: &hclHeredoc}
+	nixComments    = Commenter{LineComments: shLine, Blocks: cBlock, Strings: nixQuotes}
+	perlComments   = Commenter{LineComments: shLine, Strings: cQuotes, Lines: perlLines, Heredoc: &perlHeredoc}
+	rakuComments   = Commenter{LineComments: shLine, Strings: rakuQuotes, Lines: rakuLines}
+	prologComments = Commenter{LineComments: []string{`%`}, Blocks: cBlock, Strings: prologQuotes}
+
+	// PHP is embedded in HTML.
+	phpComments = Commenter{Blocks: xmlBlock, Regions: []Region{
+		{"<?php", "?>", &phpCode},
+		{"<?=", "?>", &phpCode},
+		{"<?", "?>", &phpCode},
+	}}
+
+	// In HAML, Pug and Slim, a comment takes in the lines indented below
+	// it.
+	hamlComments = Commenter{Lines: hamlLines}
+	pugComments  = Commenter{Lines: pugLines}
+	slimComments = Commenter{Lines: slimLines}
+
+	// ColdFusion's <!--- ---> comments nest, and <cfscript> holds script
+	// code, which is all there is to many components.
+	cfmComments = Commenter{Blocks: cfBlocks, Regions: []Region{
+		{"<cfscript>", "</cfscript>", &cfcComments},
+		{"<script", "</script>", &jsComments},
+	}}
+	cfcComments = Commenter{LineComments: cLine, Blocks: append(cfBlocks, cBlock...), Strings: cQuotes}
+
+	// Single-file components hold a template, scripts and styles.
+	sfcComments = Commenter{Blocks: xmlBlock, Regions: []Region{
+		{"<script", "</script>", &jsComments},
+		{"<style", "</style>", &cssComments},
+	}}
+
+	// TeX ends comments at the end of the line, AsciiDoc only has them
+	// from the start of a line, and reStructuredText and Org set them apart
+	// in ways their LineFuncs tell.
+	texComments  = Commenter{LineComments: []string{`%`}, Blocks: []Block{{`\begin{comment}`, `\end{comment}`, false}}, Strings: texQuotes}
+	adocComments = Commenter{LeadComments: cLine, Blocks: []Block{{`////`, `////`, false}}, LineStart: true}
+	rstComments  = Commenter{Lines: rstLines}
+	orgComments  = Commenter{Lines: orgLines}
+
+	// Templates have comments of their own besides those of HTML.
+	jinjaComments  = Commenter{Blocks: []Block{{`{#`, `#}`, false}, xmlBlock[0]}}
+	erbComments    = Commenter{Blocks: []Block{{`<%#`, `%>`, false}, xmlBlock[0]}}
+	hbsComments    = Commenter{Blocks: []Block{{`{{!--`, `--}}`, false}, {`{{!`, `}}`, false}, xmlBlock[0]}}
+	liquidComments = Commenter{Blocks: []Block{{`{% comment %}`, `{% endcomment %}`, false}, {`{% #`, `%}`, false}, xmlBlock[0]}}
+
+	// Pascal's compiler directives such as {$IFDEF DEBUG} look like
+	// comments but are code.
+	pascalComments = Commenter{
+		LineComments: cLine,
+		Blocks:       []Block{{`{`, `}`, false}, {`(*`, `*)`, false}},
+		Strings:      plainSQuotes,
+		Regions:      []Region{{"{$", "}", &noComments}, {"(*$", "*)", &noComments}},
+	}
+
+	// Fixed-form Fortran marks comment lines by column, free-form only
+	// with "!".
+	fortranComments      = Commenter{LineComments: []string{`!`}, Strings: plainQuotes}
+	fixedFortranComments = Commenter{LineComments: []string{`!`}, Strings: plainQuotes, Lines: fixedFortranLines}
+
+	// COBOL marks comment lines by column too, unless in free format, and
+	// so does ABAP.
+	cobolComments = Commenter{LineComments: []string{`*>`}, Strings: plainQuotes, Lines: cobolLines}
+	abapComments  = Commenter{LineComments: []string{`"`}, Strings: abapQuotes, Lines: abapLines}
+
+	// Batch files comment with the REM command, or with "::", which is an
+	// odd label.
+	batchComments = Commenter{LeadComments: []string{"rem", "@rem", "::"}, Strings: plainDQuotes}
+
+	// Dockerfiles, sed scripts and Apache's configuration only have
+	// comments at the start of a line; a # later on is part of the
+	// instruction, command or directive.
+	leadShComments = Commenter{LeadComments: shLine}
+
+	// In configuration files, comments run to the end of the line, but in
+	// INI and properties files only from its start.
+	yamlComments = Commenter{LineComments: shLine, Strings: shQuotes, Lines: yamlLines}
+	tomlComments = Commenter{LineComments: shLine, Strings: tomlQuotes}
+	iniComments  = Commenter{LeadComments: []string{";", "#"}}
+	propComments = Commenter{LeadComments: []string{"#", "!"}}
+
+	// SAS statements starting with * are comments up to their ;. Stata
+	// comments with a * starting the line.
+	sasComments   = Commenter{Blocks: cBlock, LeadBlocks: []Block{{`*`, `;`, false}, {`%*`, `;`, false}}, Strings: plainQuotes}
+	stataComments = Commenter{LineComments: cLine, LeadComments: []string{`*`}, Blocks: cBlock, Strings: plainDQuotes}
+
+	// Vim script comments with ", which also starts strings. One that
+	// starts a line, or is never closed, is a comment.
+	vimComments = Commenter{Strings: vimQuotes, Lines: vimLines}
+
+	// Smalltalk puts comments in double quotes, and strings in single ones.
+	stComments = Commenter{Blocks: []Block{{`"`, `"`, false}}, Strings: stQuotes}
+
+	// AppleScript's block comments nest. AutoHotkey's ; only starts a
+	// comment after a blank, and its blocks only at the start of a line.
+	asComments  = Commenter{LineComments: []string{`--`, `#`}, Blocks: []Block{{`(*`, `*)`, true}}, Strings: dqQuotes}
+	ahkComments = Commenter{LineComments: []string{" ;", "\t;"}, LeadComments: []string{";"}, Blocks: cBlock, Strings: []Quote{{Start: `"`, End: `"`, Escape: '`'}}, LineStart: true}
+
+	// Basic's comments start with ' or REM, where a statement can.
+	vbComments = Commenter{LineComments: []string{`'`}, LeadComments: []string{"rem"}, Strings: plainDQuotes}
+
+	// R Markdown and Sweave files are prose around chunks of R.
+	rmdComments = Commenter{LineComments: shLine, Strings: shQuotes, Lines: rmdLines}
+	rnwComments = Commenter{LineComments: shLine, Strings: shQuotes, Lines: rnwLines}
+)
+
+type Language struct {
+	Namer
+	Matcher
+	Commenter
+}
+
+// Update counts the lines of c, which is UTF-8, into s.
+func (l Language) Update(c []byte, s *Stats) {
+	l.UpdateOptions(c, s, &Options{})
+}
+
+// UpdateOptions is like Update but counts according to o.
+func (l Language) UpdateOptions(c []byte, s *Stats, o *Options) {
+	s.FileCount++
+
+	sc := l.newScanner(s, o)
+	sc.scan(c)
+	sc.flush(c)
+}
+
+// readSize is how much UpdateReader reads at a time. Its buffer only grows
+// to hold lines longer than that.
+const readSize = 64 << 10
+
+// UpdateReader is like UpdateOptions but reads the content from r, without
+// holding more than a few lines of it in memory at once. The counts are
+// the same as for the whole content.
+func (l Language) UpdateReader(r io.Reader, s *Stats, o *Options) error {
+	s.FileCount++
+
+	sc := l.newScanner(s, o)
+	buf := make([]byte, 0, readSize)
+	for {
+		if len(buf) == cap(buf) {
+			buf = append(buf, 0)[:len(buf)]
+		}
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		// The scanner only looks ahead within a line, so it is given
+		// whole lines. A "\r" at the end of buf might yet be followed by
+		// "\n", so it waits for the next read.
+		i := bytes.LastIndexAny(buf, "\r\n")
+		if i >= 0 && i == len(buf)-1 && buf[i] == '\r' && err == nil {
+			i = bytes.LastIndexAny(buf[:i], "\r\n")
+		}
+		if i >= 0 {
+			sc.scan(buf[:i+1])
+			buf = buf[:copy(buf, buf[i+1:])]
+		}
+		if err == io.EOF {
+			sc.scan(buf)
+			sc.flush(buf)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (l Language) newScanner(s *Stats, o *Options) *scanner {
+	sc := &scanner{Commenter: l.Commenter, s: s, mixed: o.Mixed, lead: true, tags: o.Tags, tagLine: o.tagLine, lineClass: o.lineClass}
+	if l.Lines != nil {
+		sc.lines = l.Lines(o)
+	}
+	return sc
+}
+
+type Namer string
+
+func (l Namer) Name() string { return string(l) }
+
+// A Matcher recognizes the files of a language by their extensions, their
+// whole names or the starts or ends of their names.
+type Matcher struct {
+	Exts     []string // such as ".c", with the dot
+	Names    []string // such as "Makefile"
+	Prefixes []string // such as "Dockerfile.", matched against the base name
+	Suffixes []string // such as "_test.go"
+	Except   []string // base names that never match, such as "go.mod"
+
+	// Paths are patterns such as "roles/*/tasks/*.yml", matched with
+	// path.Match against as many of the last elements of the path.
+	Paths []string
+}
+
+func (m Matcher) Match(fname string) bool {
+	ext, base := path.Ext(fname), path.Base(fname)
+	for _, n := range m.Except {
+		if n == base {
+			return false
+		}
+	}
+	for _, e := range m.Exts {
+		if e == ext {
+			return true
+		}
+	}
+	for _, n := range m.Names {
+		if n == base {
+			return true
+		}
+	}
+	for _, prefix := range m.Prefixes {
+		if strings.HasPrefix(base, prefix) {
+			return true
+		}
+	}
+	for _, suffix := range m.Suffixes {
+		if strings.HasSuffix(fname, suffix) {
+			return true
+		}
+	}
+	for _, p := range m.Paths {
+		if matchPathEnd(p, fname) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathEnd reports whether the last elements of fname match the
+// pattern p, which has as many.
+func matchPathEnd(p, fname string) bool {
+	elems := strings.Split(fname, "/")
+	n := strings.Count(p, "/") + 1
+	if len(elems) < n {
+		return false
+	}
+	ok, _ := path.Match(p, strings.Join(elems[len(elems)-n:], "/"))
+	return ok
+}
+
+func mExt(exts ...string) Matcher { return Matcher{Exts: exts} }
+
+func mName(names ...string) Matcher { return Matcher{Names: names} }
+
+func mSuffix(suffixes ...string) Matcher { return Matcher{Suffixes: suffixes} }
+
+// Rename returns a copy of langs with the languages named in names
+// renamed, so that their files are counted under the new names.
+func Rename(langs []Language, names map[string]string) []Language {
+	r := make([]Language, len(langs))
+	for i, l := range langs {
+		if n, ok := names[l.Name()]; ok {
+			l.Namer = Namer(n)
+		}
+		r[i] = l
+	}
+	return r
+}
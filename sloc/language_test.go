@@ -0,0 +1,423 @@
+package sloc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// language returns the language in the table with the given name that
+// matches the file name.
+func language(name, fname string) (Language, bool) {
+	for _, l := range Languages {
+		if l.Name() == name && l.Match(fname) {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
+
+func TestUpdateLastLine(t *testing.T) {
+	goLang, _ := language("Go", "a.go")
+	tests := []struct {
+		name                        string
+		content                     string
+		total, code, comment, blank int
+	}{
+		{"empty", "", 0, 0, 0, 0},
+		{"single newline", "\n", 1, 0, 0, 1},
+		{"two newlines", "\n\n", 2, 0, 0, 2},
+		{"code with newline", "package main\n", 1, 1, 0, 0},
+		{"code without newline", "package main", 1, 1, 0, 0},
+		{"comment with newline", "package main\n// end\n", 2, 1, 1, 0},
+		{"comment without newline", "package main\n// end", 2, 1, 1, 0},
+		{"blank without newline", "package main\n  ", 2, 1, 0, 1},
+		{"open block without newline", "/* a\nb", 2, 0, 2, 0},
+		{"CRLF without newline", "package main\r\nfunc main() {}", 2, 2, 0, 0},
+		{"CR only", "package main\rfunc main() {}\r", 2, 2, 0, 0},
+		{"CR without newline", "package main\r\r// end", 3, 1, 1, 1},
+		{"CRLF blank lines", "\r\n\r\n", 2, 0, 0, 2},
+		{"LF then CR", "\n\r", 2, 0, 0, 2},
+		{"no-break spaces", "\u00a0\u00a0\n\u2003\u3000", 2, 0, 0, 2},
+		{"code after a wide space", "\u3000x := 1\n", 1, 1, 0, 0},
+		{"CJK comment", "// 注释\n", 1, 0, 1, 0},
+		{"emoji in a string", "s := \"😀 // 🎉\"\n", 1, 1, 0, 0},
+	}
+	for _, tt := range tests {
+		var s Stats
+		goLang.Update([]byte(tt.content), &s)
+		if s.TotalLines != tt.total || s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s: got %d total, %d code, %d comment, %d blank; want %d, %d, %d, %d",
+				tt.name, s.TotalLines, s.CodeLines, s.CommentLines, s.BlankLines,
+				tt.total, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
+
+// languageTests count a fixture in testdata for each language. The
+// fixtures with an extension that several languages share tell them apart.
+var languageTests = []struct {
+	file, lang           string
+	code, comment, blank int
+}{
+	{"service.thrift", "Thrift", 8, 2, 2},
+	{"user.proto", "Protocol Buffers", 8, 2, 3},
+	{"addressbook.capnp", "Cap'n Proto", 5, 1, 0},
+	{"monster.fbs", "FlatBuffers", 6, 1, 2},
+	{"mail.avdl", "Avro IDL", 7, 3, 0},
+	{"schema.graphql", "GraphQL", 9, 5, 1},
+
+	{"hello.c", "C", 6, 4, 1},
+	{"shade.frag", "GLSL", 8, 2, 2},
+	{"blur.hlsl", "HLSL", 6, 2, 2},
+	{"shade.wgsl", "WGSL", 4, 2, 0},
+	{"add.cu", "CUDA", 6, 1, 1},
+	{"vadd.cl", "OpenCL", 6, 1, 1},
+	{"scale.metal", "Metal", 6, 1, 1},
+	{"stack.cpp", "C++", 10, 2, 2},
+	{"Program.cs", "C#", 11, 2, 1},
+	{"hello.go", "Go", 8, 2, 3},
+	{"hello_test.go", "GoTest", 5, 1, 2},
+
+	{"main.zig", "Zig", 8, 2, 1},
+	{"hello.nim", "Nim", 6, 5, 1},
+	{"server.cr", "Crystal", 9, 1, 2},
+	{"hello.v", "V", 5, 3, 2},
+	{"mux.v", "Verilog", 4, 2, 0},
+	{"counter_tb.sv", "SystemVerilog", 11, 2, 2},
+	{"adder.vhd", "VHDL", 10, 3, 2},
+	{"Nat.v", "Coq", 8, 1, 2},
+	{"point.rs", "Rust", 10, 2, 1},
+	{"nested.rs", "Rust", 0, 5, 0},
+	{"raw.rs", "Rust", 6, 3, 2},
+	{"Main.scala", "Scala", 5, 2, 0},
+	{"Hello.java", "Java", 6, 2, 1},
+	{"hello.vala", "Vala", 5, 2, 0},
+	{"hello.gs", "Genie", 2, 2, 0},
+	{"Token.sol", "Solidity", 11, 7, 2},
+	{"Parser.kt", "Kotlin", 7, 5, 2},
+	{"build.gradle.kts", "Kotlin", 6, 2, 1},
+	{"Jenkinsfile", "Groovy", 18, 2, 0},
+	{"build.gradle", "Groovy", 9, 4, 2},
+	{"Shapes.swift", "Swift", 12, 4, 2},
+	{"app.d", "D", 8, 5, 2},
+	{"types.di", "D", 2, 2, 1},
+
+	{"calc.y", "YACC", 6, 1, 3},
+	{"words.l", "Lex", 7, 1, 0},
+
+	{"queue.lua", "Lua", 6, 2, 2},
+	{"brackets.lua", "Lua", 9, 9, 1},
+
+	{"schema.sql", "SQL", 5, 2, 1},
+
+	{"Fib.hs", "Haskell", 3, 2, 1},
+	{"Queue.lhs", "Haskell", 4, 4, 4},
+	{"Stack.lhs", "Haskell", 3, 8, 2},
+	{"tree.ml", "ML", 6, 1, 1},
+	{"shapes.ml", "ML", 10, 5, 2},
+	{"Geometry.fs", "F#", 5, 2, 2},
+	{"Main.elm", "Elm", 4, 5, 2},
+	{"Main.purs", "PureScript", 4, 2, 2},
+	{"Shapes.re", "Reason", 7, 2, 2},
+	{"Button.res", "ReScript", 4, 2, 0},
+
+	{"greet.pl", "Perl", 4, 2, 1},
+	{"inventory.pm", "Perl", 9, 11, 5},
+	{"usage.pl", "Perl", 7, 1, 0},
+	{"family.pl", "Prolog", 5, 3, 3},
+	{"rules.pro", "Prolog", 2, 1, 1},
+	{"hello.raku", "Raku", 4, 8, 2},
+	{"today.php", "PHP", 3, 1, 1},
+	{"visits.php", "PHP", 6, 4, 1},
+	{"page.php", "PHP", 7, 1, 0},
+	{"order.php", "PHP", 11, 3, 0},
+
+	{"build.sh", "Shell", 3, 2, 1},
+	{"motd.sh", "Shell", 12, 2, 0},
+	{"deploy.bash", "Bash", 6, 2, 1},
+	{".bashrc", "Bash", 2, 1, 0},
+	{".zshrc", "Zsh", 4, 1, 1},
+	{"setup.csh", "C Shell", 2, 1, 0},
+	{"greet.fish", "Fish", 4, 1, 0},
+	{"summary.r", "R", 3, 1, 1},
+	{"report.Rmd", "R Markdown", 3, 9, 4},
+	{"model.Rnw", "Sweave", 3, 8, 2},
+	{"server.tcl", "Tcl", 6, 1, 1},
+
+	{"area.m", "MATLAB", 3, 1, 0},
+	{"Counter.m", "Objective-C", 10, 2, 2},
+	{"Renderer.mm", "Objective-C++", 10, 1, 2},
+
+	{"greeter.rb", "Ruby", 8, 2, 1},
+	{"timer.rb", "Ruby", 13, 5, 2},
+	{"report.rb", "Ruby", 5, 1, 0},
+	{"primes.py", "Python", 5, 2, 2},
+	{"queries.py", "Python", 14, 8, 5},
+	{"bazel/BUILD", "Starlark", 7, 1, 2},
+	{"bazel/defs.bzl", "Starlark", 2, 7, 1},
+	{"Stats.wl", "Mathematica", 6, 4, 2},
+	{"fib.mpl", "Maple", 4, 1, 1},
+	{"report.sas", "SAS", 6, 4, 1},
+	{"clean.do", "Stata", 4, 4, 1},
+	{"tidy.ado", "Stata", 3, 1, 0},
+	{"stats.jl", "Julia", 9, 9, 2},
+	{"exit.asm", "Assembly", 6, 2, 1},
+	{"hello.s", "Assembly", 12, 2, 0},
+	{"entry.S", "Assembly", 7, 2, 1},
+	{"blink.s", "ARM Assembly", 8, 2, 0},
+	{"start.S", "ARM Assembly", 10, 1, 0},
+	{"max.ll", "LLVM IR", 9, 1, 2},
+	{"add.wat", "WebAssembly", 6, 3, 1},
+	{"Regs.td", "TableGen", 7, 2, 1},
+	{"fact.lisp", "Lisp", 4, 2, 0},
+	{"util.cl", "Lisp", 4, 2, 1},
+	{"fib.scm", "Scheme", 5, 1, 1},
+	{"Counter.st", "Smalltalk", 9, 2, 2},
+	{"hello.rkt", "Racket", 4, 3, 0},
+	{"core.clj", "Clojure", 10, 1, 3},
+	{"config.edn", "Clojure", 2, 1, 0},
+	{"init.el", "Emacs Lisp", 5, 2, 1},
+	{"plugin.vim", "Vim script", 9, 2, 1},
+	{".vimrc", "Vim script", 2, 1, 0},
+
+	{"Makefile", "Make", 5, 1, 2},
+	{"CMakeLists.txt", "CMake", 3, 1, 1},
+	{"Jamfile", "Jam", 1, 1, 0},
+	{"viewer.pro", "QMake", 5, 1, 1},
+	{"common.pri", "QMake", 1, 1, 0},
+
+	{"notes.md", "Markdown", 4, 0, 2},
+	{"paper.tex", "LaTeX", 5, 4, 1},
+	{"thesis.cls", "LaTeX", 3, 1, 0},
+	{"guide.rst", "reStructuredText", 6, 4, 5},
+	{"manual.adoc", "AsciiDoc", 2, 5, 1},
+	{"notes.org", "Org", 3, 5, 0},
+
+	{"page.haml", "HAML", 4, 0, 0},
+	{"show.haml", "HAML", 3, 4, 1},
+	{"index.pug", "Pug", 5, 3, 0},
+	{"layout.slim", "Slim", 4, 2, 0},
+	{"style.sass", "SASS", 4, 1, 1},
+	{"card.scss", "SCSS", 5, 1, 1},
+	{"theme.less", "Less", 7, 2, 1},
+	{"layout.styl", "Stylus", 5, 4, 1},
+	{"app.pcss", "PostCSS", 4, 1, 1},
+
+	{"index.html", "HTML", 9, 1, 0},
+	{"config.xml", "XML", 5, 1, 1},
+	{"app.yml", "YAML", 10, 1, 1},
+	{"ingress.yaml", "YAML", 7, 0, 0},
+	{"deploy.yml", "Ansible", 6, 0, 0},
+	{"playbooks/web.yml", "Ansible", 3, 1, 0},
+	{"roles/web/tasks/main.yml", "Ansible", 5, 1, 0},
+	{"site.pp", "Puppet", 11, 2, 1},
+	{"config.toml", "TOML", 7, 1, 0},
+	{"app.ini", "INI", 3, 2, 0},
+	{"app.properties", "Properties", 2, 2, 1},
+	{"package.json", "JSON", 4, 0, 1},
+	{"settings.jsonc", "JSONC", 4, 2, 0},
+	{".editorconfig", "Editor/VCS config", 5, 2, 1},
+	{".gitattributes", "Editor/VCS config", 1, 1, 0},
+	{"nginx.conf", "Nginx", 9, 1, 1},
+	{"httpd.conf", "Apache", 6, 2, 1},
+	{".htaccess", "Apache", 2, 1, 0},
+	{"Caddyfile", "Caddyfile", 4, 1, 0},
+	{"haproxy.cfg", "HAProxy", 6, 1, 1},
+	{"style.css", "CSS", 5, 4, 1},
+	{"clicks.js", "JavaScript", 5, 2, 1},
+	{"unicode.js", "JavaScript", 4, 3, 2},
+	{"shape.ts", "TypeScript", 9, 1, 1},
+	{"types.d.ts", "TypeScript (decl)", 4, 2, 1},
+	{"Button.jsx", "JSX", 11, 1, 1},
+	{"Greeting.tsx", "TSX", 7, 1, 1},
+	{"square.coffee", "CoffeeScript", 2, 1, 1},
+	{"Card.vue", "Vue", 18, 5, 3},
+	{"Counter.svelte", "Svelte", 9, 3, 2},
+	{"Main.hx", "Haxe", 5, 2, 0},
+	{"Greeter.as", "ActionScript", 5, 2, 0},
+	{"Viewer.qml", "QML", 9, 2, 1},
+	{"base.j2", "Jinja", 4, 5, 0},
+	{"list.twig", "Twig", 5, 2, 0},
+	{"show.html.erb", "ERB", 2, 5, 0},
+	{"post.hbs", "Handlebars", 4, 3, 0},
+	{"user.mustache", "Mustache", 3, 1, 0},
+	{"product.liquid", "Liquid", 4, 6, 0},
+
+	{"hello.erl", "Erlang", 4, 2, 1},
+	{"queue_srv.erl", "Erlang", 9, 3, 4},
+	{"records.hrl", "Erlang", 1, 1, 0},
+	{"page_controller.ex", "Elixir", 16, 9, 3},
+
+	{"sum.awk", "AWK", 2, 2, 1},
+	{"count.awk", "AWK", 3, 1, 0},
+	{"fix.sed", "sed", 3, 2, 0},
+
+	{"main.tf", "HCL", 13, 3, 1},
+	{"terraform.tfvars", "HCL", 1, 1, 0},
+	{"hello.nix", "Nix", 14, 2, 1},
+	{"flake.nix", "Nix", 7, 1, 2},
+	{"Dockerfile", "Dockerfile", 8, 2, 1},
+	{"Dockerfile.dev", "Dockerfile", 3, 1, 0},
+	{"app.dockerfile", "Dockerfile", 2, 0, 0},
+	{"Containerfile", "Dockerfile", 1, 1, 0},
+
+	{"roots.f90", "Fortran", 14, 2, 1},
+	{"squares.f", "Fortran", 13, 4, 1},
+	{"Shapes.pas", "Pascal", 16, 3, 7},
+	{"hello.pp", "Pascal", 5, 1, 0},
+	{"Stack.mod", "Modula-2", 6, 3, 2},
+	{"Stack.def", "Modula-2", 3, 1, 0},
+	{"Hello.ob2", "Oberon", 5, 1, 0},
+	{"greet.ads", "Ada", 3, 1, 0},
+	{"greet.adb", "Ada", 7, 1, 1},
+	{"account.e", "Eiffel", 10, 1, 5},
+	{"build.bat", "Batch", 9, 5, 1},
+	{"notify.applescript", "AppleScript", 2, 4, 0},
+	{"hotkeys.ahk", "AutoHotkey", 5, 4, 0},
+	{"deploy.ps1", "PowerShell", 11, 5, 1},
+	{"Tools.psd1", "PowerShell", 10, 2, 0},
+	{"Greeter.vb", "Visual Basic", 8, 2, 1},
+	{"cleanup.vbs", "Visual Basic", 4, 2, 0},
+	{"Module1.bas", "Visual Basic", 4, 1, 0},
+	{"Account.cls", "Visual Basic", 9, 1, 1},
+	{"Invoice.cls", "Apex", 9, 3, 2},
+	{"AccountTrigger.trigger", "Apex", 5, 1, 0},
+	{"index.cfm", "ColdFusion", 7, 4, 0},
+	{"Cart.cfc", "ColdFusion", 6, 4, 1},
+	{"zreport.abap", "ABAP", 5, 4, 1},
+	{"Main.frm", "Visual Basic", 8, 1, 0},
+	{"payroll.cbl", "COBOL", 10, 2, 2},
+	{"employee.cpy", "COBOL", 4, 2, 0},
+	{"hello.cob", "COBOL", 6, 1, 1},
+}
+
+func TestLanguages(t *testing.T) {
+	for _, tt := range languageTests {
+		c := NewCounter()
+		if err := c.CountFile(filepath.Join("testdata", tt.file)); err != nil {
+			t.Error(err)
+			continue
+		}
+		r := c.Results()
+		s, ok := r[tt.lang]
+		if !ok {
+			t.Errorf("%s: counted as %v, not %s", tt.file, r, tt.lang)
+			continue
+		}
+		if s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s as %s: got %d code, %d comment, %d blank; want %d, %d, %d",
+				tt.file, tt.lang, s.CodeLines, s.CommentLines, s.BlankLines, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
+
+func TestUpdateReader(t *testing.T) {
+	type content struct{ lang, fname, text string }
+	var tests []content
+	for _, tt := range languageTests {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tests = append(tests, content{tt.lang, tt.file, string(b)})
+	}
+	// Markers and strings straddling the reads of UpdateReader.
+	long := strings.Repeat("x", readSize-1)
+	tests = append(tests,
+		content{"Go", "a.go", long + "/* a\nb */\nx := 1\n"},
+		content{"Go", "a.go", "x := 1\n" + long + "\"//\"\n// b"},
+		content{"Go", "a.go", strings.Repeat("// a comment\n", readSize/7)},
+		content{"Python", "a.py", "\"\"\"\n" + long + "\n\"\"\"\nx = 1\n"},
+	)
+	for _, tt := range tests {
+		l, ok := language(tt.lang, tt.fname)
+		if !ok {
+			t.Fatalf("%s does not match %s", tt.fname, tt.lang)
+		}
+		var want Stats
+		l.UpdateOptions([]byte(tt.text), &want, &Options{})
+		readers := map[string]func(io.Reader) io.Reader{
+			"whole":    func(r io.Reader) io.Reader { return r },
+			"one byte": iotest.OneByteReader,
+			"halves":   iotest.HalfReader,
+		}
+		for name, reader := range readers {
+			if name == "one byte" && len(tt.text) > readSize {
+				continue // finding the end of a long line a byte at a time is slow
+			}
+			var got Stats
+			if err := l.UpdateReader(reader(bytes.NewReader([]byte(tt.text))), &got, &Options{}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s read %s: got %+v, want %+v", tt.fname, name, got, want)
+			}
+		}
+	}
+}
+
+func TestDockerfileNames(t *testing.T) {
+	tests := []struct{ file, want string }{
+		{"Dockerfile", "Dockerfile"},
+		{"docker/Dockerfile.alpine", "Dockerfile"},
+		{"Containerfile.test", "Dockerfile"},
+		{"web.dockerfile", "Dockerfile"},
+		{"Dockerfile.md", "Markdown"},
+		{"Dockerfile.ps1", "PowerShell"},
+		{"Dockerfiles", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		for _, l := range Languages {
+			if l.Match(tt.file) {
+				got = l.Name()
+				break
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s: counted as %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestUnmatched(t *testing.T) {
+	for _, f := range []string{"terraform.tfstate", "terraform.tfstate.backup", "go.mod", "tools/go.mod"} {
+		for _, l := range Languages {
+			if l.Match(f) {
+				t.Errorf("%s counts as %s", f, l.Name())
+			}
+		}
+	}
+}
+
+func TestLanguagesCovered(t *testing.T) {
+	tested := map[string]bool{}
+	for _, tt := range languageTests {
+		tested[tt.lang] = true
+	}
+	for _, l := range Languages {
+		if !tested[l.Name()] {
+			t.Errorf("%s has no fixture in languageTests", l.Name())
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	c := NewCounter()
+	c.Languages = Rename(Languages, map[string]string{"JSX": "JavaScript", "TSX": "TypeScript"})
+	for _, f := range []string{"clicks.js", "Button.jsx", "shape.ts", "Greeting.tsx"} {
+		if err := c.CountFile(filepath.Join("testdata", f)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := c.Results()
+	if len(r) != 2 || r["JavaScript"].FileCount != 2 || r["TypeScript"].FileCount != 2 {
+		t.Errorf("got %v, want two JavaScript and two TypeScript files", r)
+	}
+}
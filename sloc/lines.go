@@ -0,0 +1,590 @@
+package sloc
+
+import (
+	"bytes"
+)
+
+// Options adjusts how lines are counted.
+type Options struct {
+	// DataAsComments counts the data after Perl's __END__ or __DATA__ as
+	// comments instead of leaving it out.
+	DataAsComments bool
+
+	// RawCellsAsBlank counts the raw cells of Jupyter notebooks as blank
+	// lines instead of leaving them out.
+	RawCellsAsBlank bool
+
+	// DocsAsCode counts Elixir's @moduledoc, @typedoc and @doc heredocs
+	// as code instead of comments.
+	DocsAsCode bool
+
+	// PragmasAsCode counts Haskell's {-# ... #-} pragmas as code instead
+	// of comments.
+	PragmasAsCode bool
+
+	// DiscardedAsComments counts the forms that Lisps read but then drop,
+	// such as Clojure's #_ and (comment ...) and Scheme's #;, as comments
+	// instead of code.
+	DiscardedAsComments bool
+
+	// Mixed decides what a line holding both code and a comment counts
+	// as.
+	Mixed Mixed
+
+	// Tags are words such as TODO that are counted, into Stats.Tags,
+	// wherever they appear in comments. Case matters.
+	Tags []string
+
+	tagLine   func(tag string, line int) // called for each tag found
+	lineClass func(c Class)              // called with what each line counts as
+}
+
+// A Mixed is a policy for lines holding both code and a comment.
+type Mixed int
+
+const (
+	MixedCode    Mixed = iota // count them as code
+	MixedComment              // count them as comments
+	MixedBoth                 // count them as both, though only once in TotalLines
+)
+
+// A Class is what a line counts as.
+type Class int
+
+const (
+	Unknown Class = iota
+	Code
+	Comment
+	Blank
+	Skip // not counted at all
+)
+
+// A LineFunc sees each line that starts outside comments and strings
+// before the scanner does, and may classify the whole line itself. It
+// returns Unknown to leave the line to the scanner. The line does not
+// include its newline.
+type LineFunc func(line []byte) Class
+
+func isLetter(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// perlLines handles POD, which runs from any line starting with "=" and a
+// word through the next "=cut" line, and the data after __END__ or
+// __DATA__.
+func perlLines(o *Options) LineFunc {
+	pod, data := false, false
+	return func(line []byte) Class {
+		switch {
+		case data:
+			if o.DataAsComments {
+				return Comment
+			}
+			return Skip
+		case len(line) > 1 && line[0] == '=' && isLetter(line[1]):
+			pod = !bytes.HasPrefix(line, []byte("=cut"))
+			return Comment
+		case pod:
+			return Comment
+		case bytes.HasPrefix(line, []byte("__END__")) || bytes.HasPrefix(line, []byte("__DATA__")):
+			data = true
+			return Code
+		}
+		return Unknown
+	}
+}
+
+// rakuLines handles Pod, Raku's documentation: the blocks from =begin to
+// the =end of the same name, the paragraphs of =for, =head1 and the like
+// up to a blank line, and everything after =finish, which is like Perl's
+// data.
+func rakuLines(o *Options) LineFunc {
+	end := "" // the name of the =begin block being read, if any
+	para, data := false, false
+	return func(line []byte) Class {
+		t := bytes.TrimLeft(line, " \t")
+		switch {
+		case data:
+			if o.DataAsComments {
+				return Comment
+			}
+			return Skip
+		case end != "":
+			if f := bytes.Fields(t); len(f) > 1 && string(f[0]) == "=end" && string(f[1]) == end {
+				end = ""
+			}
+			return Comment
+		case para && len(bytes.TrimSpace(t)) == 0:
+			para = false
+			return Blank
+		case para:
+			return Comment
+		case len(t) > 1 && t[0] == '=' && isLetter(t[1]):
+			f := bytes.Fields(t)
+			switch string(f[0]) {
+			case "=begin":
+				end = "pod"
+				if len(f) > 1 {
+					end = string(f[1])
+				}
+			case "=finish":
+				data = true
+			default:
+				para = true
+			}
+			return Comment
+		}
+		return Unknown
+	}
+}
+
+// fixedFortranLines handles the columns of fixed-form Fortran, where a C,
+// c, * or ! in column 1 makes the line a comment and any other character
+// but 0 in column 6 makes it the continuation of the statement before,
+// even a !. Lines indented by a tab have no columns.
+func fixedFortranLines(o *Options) LineFunc {
+	return func(line []byte) Class {
+		if len(line) == 0 {
+			return Unknown
+		}
+		switch line[0] {
+		case 'C', 'c', '*', '!':
+			return Comment
+		}
+		if len(line) < 6 || line[5] == ' ' || line[5] == '0' {
+			return Unknown
+		}
+		for _, b := range line[:5] {
+			if b != ' ' && (b < '0' || b > '9') {
+				return Unknown
+			}
+		}
+		return Code
+	}
+}
+
+// abapLines counts the lines with a * in column 1 as comments.
+func abapLines(o *Options) LineFunc {
+	return func(line []byte) Class {
+		if len(line) > 0 && line[0] == '*' {
+			return Comment
+		}
+		return Unknown
+	}
+}
+
+// vimLines counts the lines starting with " as comments, whatever else
+// they hold.
+func vimLines(o *Options) LineFunc {
+	return func(line []byte) Class {
+		if t := bytes.TrimLeft(line, " \t"); len(t) > 0 && t[0] == '"' {
+			return Comment
+		}
+		return Unknown
+	}
+}
+
+// hamlLines handles HAML's comments, which start with / or -#.
+func hamlLines(o *Options) LineFunc {
+	return indentLines("/", "-#")
+}
+
+// pugLines handles Pug's comments, which start with //.
+func pugLines(o *Options) LineFunc {
+	return indentLines("//")
+}
+
+// slimLines handles Slim's comments, which start with /.
+func slimLines(o *Options) LineFunc {
+	return indentLines("/")
+}
+
+// indentLines counts the lines starting with one of markers as comments,
+// along with the lines after them that are indented further, or blank.
+func indentLines(markers ...string) LineFunc {
+	indent := -1 // that of the comment being read, if any
+	return func(line []byte) Class {
+		t := bytes.TrimLeft(line, " \t")
+		n := len(line) - len(t)
+		if indent >= 0 {
+			switch {
+			case len(bytes.TrimSpace(t)) == 0:
+				return Blank
+			case n > indent:
+				return Comment
+			}
+			indent = -1
+		}
+		for _, m := range markers {
+			if bytes.HasPrefix(t, []byte(m)) {
+				indent = n
+				return Comment
+			}
+		}
+		return Unknown
+	}
+}
+
+// cobolLines handles the columns of fixed-format COBOL, where columns 1 to
+// 6 hold sequence numbers, a * or / in column 7 makes the line a comment
+// and columns from 73 on are left for identification. A >>SOURCE FORMAT
+// directive switches to free format, which has no columns, and back.
+func cobolLines(o *Options) LineFunc {
+	free := false
+	return func(line []byte) Class {
+		if u := bytes.ToUpper(line); bytes.Contains(u, []byte(">>SOURCE")) || bytes.Contains(u, []byte("SOURCEFORMAT")) {
+			free = bytes.Contains(u, []byte("FREE"))
+			return Code
+		}
+		if !free {
+			if len(line) > 6 && (line[6] == '*' || line[6] == '/') {
+				return Comment
+			}
+			if len(line) > 72 {
+				line = line[:72]
+			}
+			if len(line) < 6 {
+				return Blank
+			}
+			line = line[6:]
+		}
+		t := bytes.TrimSpace(line)
+		switch {
+		case len(t) == 0:
+			return Blank
+		case bytes.HasPrefix(t, []byte("*>")):
+			return Comment
+		}
+		return Unknown
+	}
+}
+
+// elixirLines counts the heredocs of Elixir's @moduledoc, @typedoc and
+// @doc attributes as comments, as they are documentation.
+func elixirLines(o *Options) LineFunc {
+	var end string // what closes the doc being read, if any
+	return func(line []byte) Class {
+		t := bytes.TrimSpace(line)
+		switch {
+		case end != "":
+			if bytes.HasPrefix(t, []byte(end)) {
+				end = ""
+			}
+			return Comment
+		case o.DocsAsCode:
+			return Unknown
+		}
+		if end = elixirDoc(t); end != "" {
+			return Comment
+		}
+		return Unknown
+	}
+}
+
+// elixirDoc returns the delimiter closing the doc heredoc opened by line,
+// or "" if it opens none. The heredoc may be a sigil such as ~S""".
+func elixirDoc(line []byte) string {
+	for _, attr := range []string{"@moduledoc", "@typedoc", "@doc"} {
+		if !bytes.HasPrefix(line, []byte(attr)) {
+			continue
+		}
+		rest := bytes.TrimLeft(line[len(attr):], " \t")
+		if len(rest) == len(line)-len(attr) {
+			return ""
+		}
+		if len(rest) > 2 && rest[0] == '~' && isLetter(rest[1]) {
+			rest = rest[2:]
+		}
+		if string(rest) == `"""` || string(rest) == `'''` {
+			return string(rest)
+		}
+		return ""
+	}
+	return ""
+}
+
+// hsLines counts the pragmas of Haskell, such as {-# LANGUAGE GADTs #-},
+// as code when o says so. They are comments otherwise.
+func hsLines(o *Options) LineFunc {
+	pragma := false
+	return func(line []byte) Class {
+		if !o.PragmasAsCode {
+			return Unknown
+		}
+		if pragma || bytes.HasPrefix(bytes.TrimSpace(line), []byte("{-#")) {
+			pragma = !bytes.Contains(line, []byte("#-}"))
+			return Code
+		}
+		return Unknown
+	}
+}
+
+// lhsLines handles literate Haskell, where only the lines marked with ">"
+// and those between \begin{code} and \end{code} are code, and the rest is
+// commentary.
+func lhsLines(o *Options) LineFunc {
+	code := false
+	hs := hsLines(o)
+	return func(line []byte) Class {
+		t := bytes.TrimSpace(line)
+		switch {
+		case bytes.HasPrefix(t, []byte(`\begin{code}`)):
+			code = true
+			return Comment
+		case bytes.HasPrefix(t, []byte(`\end{code}`)):
+			code = false
+			return Comment
+		case code:
+			return hs(line)
+		case len(line) > 0 && line[0] == '>':
+			return birdTrack(bytes.TrimSpace(line[1:]), hs)
+		case len(t) == 0:
+			return Blank
+		}
+		return Comment
+	}
+}
+
+// birdTrack classifies the line of code t, which followed a ">", without
+// tracking comments across lines.
+func birdTrack(t []byte, hs LineFunc) Class {
+	if c := hs(t); c != Unknown {
+		return c
+	}
+	switch {
+	case len(t) == 0:
+		return Blank
+	case bytes.HasPrefix(t, []byte("--")) || bytes.HasPrefix(t, []byte("{-")):
+		return Comment
+	}
+	return Code
+}
+
+// rmdLines handles R Markdown, where the chunks fenced by ```{r} and ```
+// are code and the rest is prose.
+func rmdLines(o *Options) LineFunc {
+	return chunkLines(func(t []byte) bool {
+		return bytes.HasPrefix(t, []byte("```{"))
+	}, func(t []byte) bool {
+		return len(bytes.Trim(t, "`")) == 0 && len(t) >= 3
+	}, "`r ")
+}
+
+// rnwLines handles Sweave, where the chunks from a <<...>>= line to an @
+// line are code and the rest is LaTeX prose.
+func rnwLines(o *Options) LineFunc {
+	return chunkLines(func(t []byte) bool {
+		return bytes.HasPrefix(t, []byte("<<")) && bytes.HasSuffix(t, []byte(">>="))
+	}, func(t []byte) bool {
+		return len(t) > 0 && t[0] == '@' && (len(t) == 1 || isBlank(t[1]))
+	}, `\Sexpr{`)
+}
+
+// chunkLines leaves the lines of the chunks between the lines that start
+// and end recognize to the scanner, and counts the lines of prose as
+// comments, or as code if they hold an inline expression starting with
+// inline. The lines delimiting chunks are prose.
+func chunkLines(start, end func(t []byte) bool, inline string) LineFunc {
+	code := false
+	return func(line []byte) Class {
+		t := bytes.TrimSpace(line)
+		switch {
+		case code && end(t):
+			code = false
+			return Comment
+		case code:
+			return Unknown
+		case start(t):
+			code = true
+			return Comment
+		case len(t) == 0:
+			return Blank
+		case bytes.Contains(t, []byte(inline)):
+			return Code
+		}
+		return Comment
+	}
+}
+
+// yamlLines counts the content of YAML block scalars, which follow a | or
+// > and are indented further than the line that has it, as code even
+// where it holds a #.
+func yamlLines(o *Options) LineFunc {
+	block := -1 // the indentation of the line opening the scalar, if any
+	return func(line []byte) Class {
+		t := bytes.TrimSpace(line)
+		indent := len(line) - len(bytes.TrimLeft(line, " "))
+		if block >= 0 {
+			switch {
+			case len(t) == 0:
+				return Blank
+			case indent > block:
+				return Code
+			}
+			block = -1
+		}
+		if i := bytes.Index(t, []byte(" #")); i >= 0 {
+			t = bytes.TrimSpace(t[:i])
+		}
+		t = bytes.TrimRight(t, "+-0123456789")
+		if n := len(t); n > 0 && (t[n-1] == '|' || t[n-1] == '>') && (n == 1 || t[n-2] == ' ') {
+			block = indent
+		}
+		return Unknown
+	}
+}
+
+// rstLines handles the comments of reStructuredText: an explicit markup
+// block starting with ".." that is no directive, target, footnote or
+// substitution, along with the lines indented under it.
+func rstLines(o *Options) LineFunc {
+	block := -1 // the indentation of the comment being read, if any
+	return func(line []byte) Class {
+		t := bytes.TrimSpace(line)
+		indent := len(line) - len(bytes.TrimLeft(line, " \t"))
+		if block >= 0 {
+			switch {
+			case len(t) == 0:
+				return Blank
+			case indent > block:
+				return Comment
+			}
+			block = -1
+		}
+		if !bytes.HasPrefix(t, []byte("..")) || (len(t) > 2 && t[2] != ' ') {
+			return Unknown
+		}
+		rest := bytes.TrimSpace(t[2:])
+		if len(rest) > 0 && (rest[0] == '_' || rest[0] == '[' || rest[0] == '|') {
+			return Unknown
+		}
+		if f := bytes.Fields(rest); len(f) > 0 && bytes.Contains(f[0], []byte("::")) {
+			return Unknown
+		}
+		block = indent
+		return Comment
+	}
+}
+
+// orgLines handles the comments of Org mode: lines of a # alone or followed
+// by a blank, and the blocks from #+BEGIN_COMMENT to #+END_COMMENT.
+func orgLines(o *Options) LineFunc {
+	block := false
+	return func(line []byte) Class {
+		t := bytes.TrimSpace(line)
+		switch {
+		case block:
+			block = !hasPrefixFold(t, "#+end_comment")
+			return Comment
+		case hasPrefixFold(t, "#+begin_comment"):
+			block = true
+			return Comment
+		case len(t) > 0 && t[0] == '#' && (len(t) == 1 || isBlank(t[1])):
+			return Comment
+		}
+		return Unknown
+	}
+}
+
+func hasPrefixFold(s []byte, prefix string) bool {
+	return len(s) >= len(prefix) && bytes.EqualFold(s[:len(prefix)], []byte(prefix))
+}
+
+// cppDirectives are the C preprocessor directives that cppLines knows.
+var cppDirectives = []string{
+	"include", "define", "undef", "if", "ifdef", "ifndef", "elif", "else", "endif",
+	"error", "warning", "pragma", "line",
+}
+
+// cppLines counts the C preprocessor lines of assembly files that go
+// through cpp, such as #include and #define, as code rather than as #
+// comments.
+func cppLines(o *Options) LineFunc {
+	return func(line []byte) Class {
+		t := bytes.TrimLeft(line, " \t")
+		if len(t) == 0 || t[0] != '#' {
+			return Unknown
+		}
+		t = bytes.TrimLeft(t[1:], " \t")
+		for _, d := range cppDirectives {
+			if bytes.HasPrefix(t, []byte(d)) && (len(t) == len(d) || !isWordByte(t[len(d)])) {
+				return Code
+			}
+		}
+		return Unknown
+	}
+}
+
+// schemeLines counts the datums after #; at the start of a line as
+// comments when o says so.
+func schemeLines(o *Options) LineFunc {
+	return discardedLines(o, "#;")
+}
+
+// cljLines counts the forms after #_ at the start of a line, and the
+// top-level (comment ...) forms, as comments when o says so.
+func cljLines(o *Options) LineFunc {
+	return discardedLines(o, "#_", "(comment")
+}
+
+// discardedLines counts the Lisp forms starting with one of markers at the
+// start of a line, through the line closing them, as comments when o says
+// so. A marker ending in "(" or a letter must start the line itself.
+func discardedLines(o *Options, markers ...string) LineFunc {
+	depth := 0     // how deep in the form the line starts
+	await := false // whether the form is yet to start
+	return func(line []byte) Class {
+		if !o.DiscardedAsComments {
+			return Unknown
+		}
+		if depth > 0 || await {
+			t := bytes.TrimSpace(line)
+			if len(t) == 0 {
+				return Blank
+			}
+			depth, await = formDepth(t, depth), false
+			return Comment
+		}
+		for _, m := range markers {
+			rest, ok := bytes.TrimLeft(line, " \t"), true
+			if isLetter(m[len(m)-1]) {
+				rest = line
+				ok = len(line) == len(m) || (len(line) > len(m) && !isWordByte(line[len(m)]))
+			}
+			if ok && bytes.HasPrefix(rest, []byte(m)) {
+				rest = bytes.TrimSpace(rest[len(m):])
+				if m[0] == '(' {
+					depth = formDepth(rest, 1)
+				} else {
+					depth, await = formDepth(rest, 0), len(rest) == 0
+				}
+				return Comment
+			}
+		}
+		return Unknown
+	}
+}
+
+// formDepth returns how deep in brackets the end of line is, starting at
+// depth, skipping strings, characters and comments.
+func formDepth(line []byte, depth int) int {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '\\':
+			i++
+		case ';':
+			return depth
+		case '"':
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return depth
+}
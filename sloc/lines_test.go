@@ -0,0 +1,163 @@
+package sloc
+
+import "testing"
+
+func TestPerlLines(t *testing.T) {
+	perl, _ := language("Perl", "a.pl")
+	tests := []struct {
+		name                        string
+		content                     string
+		opts                        Options
+		total, code, comment, blank int
+	}{
+		{"pod between subs", "sub a {}\n=head1 A\n\nabout a\n=cut\nsub b {}\n", Options{}, 6, 2, 4, 0},
+		{"pod to the end", "=pod\nx = 1;\n", Options{}, 2, 0, 2, 0},
+		{"not pod", "$x\n==1;\n=1;\n", Options{}, 3, 3, 0, 0},
+		{"end", "print 1;\n__END__\n# not counted\nprint 2;\n", Options{}, 2, 2, 0, 0},
+		{"data", "print 1;\n__DATA__\n=head1 x\nsub a {}\n", Options{}, 2, 2, 0, 0},
+		{"data as comments", "print 1;\n__DATA__\nsub a {}\n\n", Options{DataAsComments: true}, 4, 2, 2, 0},
+	}
+	for _, tt := range tests {
+		var s Stats
+		perl.UpdateOptions([]byte(tt.content), &s, &tt.opts)
+		if s.TotalLines != tt.total || s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s: got %d total, %d code, %d comment, %d blank; want %d, %d, %d, %d",
+				tt.name, s.TotalLines, s.CodeLines, s.CommentLines, s.BlankLines,
+				tt.total, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
+
+func TestElixirLines(t *testing.T) {
+	elixir, _ := language("Elixir", "a.ex")
+	tests := []struct {
+		name                        string
+		content                     string
+		opts                        Options
+		total, code, comment, blank int
+	}{
+		{"moduledoc", "@moduledoc \"\"\"\nA.\n\n\"\"\"\nx = 1\n", Options{}, 5, 1, 4, 0},
+		{"doc sigil", "@doc ~S'''\nA.\n'''\n", Options{}, 3, 0, 3, 0},
+		{"one-line doc", "@doc \"A.\"\nx = 1\n", Options{}, 2, 2, 0, 0},
+		{"string", "s = \"\"\"\n# a\n\"\"\"\n", Options{}, 3, 3, 0, 0},
+		{"docs as code", "@doc \"\"\"\nA.\n\"\"\"\n", Options{DocsAsCode: true}, 3, 3, 0, 0},
+		{"not an attribute", "@docs \"\"\"\nA.\n\"\"\"\n", Options{}, 3, 3, 0, 0},
+	}
+	for _, tt := range tests {
+		var s Stats
+		elixir.UpdateOptions([]byte(tt.content), &s, &tt.opts)
+		if s.TotalLines != tt.total || s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s: got %d total, %d code, %d comment, %d blank; want %d, %d, %d, %d",
+				tt.name, s.TotalLines, s.CodeLines, s.CommentLines, s.BlankLines,
+				tt.total, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
+
+func TestHaskellLines(t *testing.T) {
+	tests := []struct {
+		name                        string
+		fname                       string
+		content                     string
+		opts                        Options
+		total, code, comment, blank int
+	}{
+		{"pragma", "a.hs", "{-# LANGUAGE GADTs #-}\nmodule M where\n", Options{}, 2, 1, 1, 0},
+		{"pragma as code", "a.hs", "{-# LANGUAGE GADTs #-}\nmodule M where\n", Options{PragmasAsCode: true}, 2, 2, 0, 0},
+		{"long pragma as code", "a.hs", "{-# LANGUAGE GADTs,\n    RankNTypes #-}\n{- a -}\n", Options{PragmasAsCode: true}, 3, 2, 1, 0},
+		{"bird tracks", "a.lhs", "> x = 1\n>\n> -- a\ntext\n", Options{}, 4, 1, 2, 1},
+		{"bird pragma as code", "a.lhs", "> {-# LANGUAGE GADTs #-}\n", Options{PragmasAsCode: true}, 1, 1, 0, 0},
+		{"code block", "a.lhs", "\\begin{code}\nx = 1\n\n\\end{code}\ntext\n", Options{}, 5, 1, 3, 1},
+	}
+	for _, tt := range tests {
+		hs, _ := language("Haskell", tt.fname)
+		var s Stats
+		hs.UpdateOptions([]byte(tt.content), &s, &tt.opts)
+		if s.TotalLines != tt.total || s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s: got %d total, %d code, %d comment, %d blank; want %d, %d, %d, %d",
+				tt.name, s.TotalLines, s.CodeLines, s.CommentLines, s.BlankLines,
+				tt.total, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
+
+func TestYAMLLines(t *testing.T) {
+	yaml, _ := language("YAML", "a.yml")
+	tests := []struct {
+		name                        string
+		content                     string
+		total, code, comment, blank int
+	}{
+		{"literal", "a: |\n  # b\nc: 1\n# d\n", 4, 3, 1, 0},
+		{"folded and kept", "a: >+\n  # b\n\n  c\n", 4, 3, 0, 1},
+		{"commented indicator", "a: | # the script\n  # b\n", 2, 2, 0, 0},
+		{"nested", "a:\n  b: |\n    # c\n  # d\n", 4, 3, 1, 0},
+		{"not a scalar", "a: b|\n  # c\n", 2, 1, 1, 0},
+		{"list item", "- |\n  # a\n", 2, 2, 0, 0},
+	}
+	for _, tt := range tests {
+		var s Stats
+		yaml.Update([]byte(tt.content), &s)
+		if s.TotalLines != tt.total || s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s: got %d total, %d code, %d comment, %d blank; want %d, %d, %d, %d",
+				tt.name, s.TotalLines, s.CodeLines, s.CommentLines, s.BlankLines,
+				tt.total, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
+
+func TestDiscardedLines(t *testing.T) {
+	on := Options{DiscardedAsComments: true}
+	tests := []struct {
+		name                        string
+		lang, fname                 string
+		content                     string
+		opts                        Options
+		total, code, comment, blank int
+	}{
+		{"off", "Clojure", "a.clj", "#_(f\n  x)\n(g)\n", Options{}, 3, 3, 0, 0},
+		{"discard", "Clojure", "a.clj", "#_(f\n  x)\n(g)\n", on, 3, 1, 2, 0},
+		{"discard before the form", "Clojure", "a.clj", "#_\n\n(f x)\n(g)\n", on, 4, 1, 2, 1},
+		{"comment form", "Clojure", "a.clj", "(comment\n  (f \")\")\n  )\n(g)\n", on, 4, 1, 3, 0},
+		{"not a comment form", "Clojure", "a.clj", "(comments x)\n  (comment x)\n", on, 2, 2, 0, 0},
+		{"inside a line", "Clojure", "a.clj", "(f #_x)\n", on, 1, 1, 0, 0},
+		{"datum", "Racket", "a.rkt", "#;(f\n 1)\n(g)\n", on, 3, 1, 2, 0},
+		{"block", "Racket", "a.rkt", "#| a #| b |#\n|#\n(g)\n", Options{}, 3, 1, 2, 0},
+	}
+	for _, tt := range tests {
+		l, _ := language(tt.lang, tt.fname)
+		var s Stats
+		l.UpdateOptions([]byte(tt.content), &s, &tt.opts)
+		if s.TotalLines != tt.total || s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s: got %d total, %d code, %d comment, %d blank; want %d, %d, %d, %d",
+				tt.name, s.TotalLines, s.CodeLines, s.CommentLines, s.BlankLines,
+				tt.total, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
+
+func TestIndentLines(t *testing.T) {
+	tests := []struct {
+		name                        string
+		lang, fname                 string
+		content                     string
+		total, code, comment, blank int
+	}{
+		{"resumes at the indent", "Pug", "a.pug", "  // a\n    b\n  c\n", 3, 1, 2, 0},
+		{"resumes below the indent", "Pug", "a.pug", "  // a\n    b\nc\n", 3, 1, 2, 0},
+		{"blank inside", "Pug", "a.pug", "// a\n\n  b\nc\n", 4, 1, 2, 1},
+		{"blank after", "Slim", "a.slim", "/ a\n\n\nb\n", 4, 1, 1, 2},
+		{"comment after comment", "HAML", "a.haml", "/ a\n  b\n-# c\n  d\n%p\n", 5, 1, 4, 0},
+		{"not at the start", "HAML", "a.haml", "%p / a\n  b\n", 2, 2, 0, 0},
+	}
+	for _, tt := range tests {
+		l, _ := language(tt.lang, tt.fname)
+		var s Stats
+		l.Update([]byte(tt.content), &s)
+		if s.TotalLines != tt.total || s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s: got %d total, %d code, %d comment, %d blank; want %d, %d, %d, %d",
+				tt.name, s.TotalLines, s.CodeLines, s.CommentLines, s.BlankLines,
+				tt.total, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package sloc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// A notebook is the part of a Jupyter notebook in format 4 that counts.
+type notebook struct {
+	Format   int `json:"nbformat"`
+	Metadata struct {
+		Kernel struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+	Cells []struct {
+		Type   string          `json:"cell_type"`
+		Source json.RawMessage `json:"source"`
+	} `json:"cells"`
+}
+
+// source returns the text of a cell, which is stored either as a string or
+// as a list of lines.
+func source(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []byte(s), nil
+	}
+	var lines []string
+	if err := json.Unmarshal(raw, &lines); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	for _, l := range lines {
+		b.WriteString(l)
+	}
+	return b.Bytes(), nil
+}
+
+// countNotebook counts the code cells of a Jupyter notebook as the language
+// of its kernel, Python if it does not say, and its Markdown cells as
+// comments.
+func (c *Counter) countNotebook(name string, r io.Reader) error {
+	var nb notebook
+	if err := json.NewDecoder(r).Decode(&nb); err != nil {
+		return fmt.Errorf("%s: not a notebook: %s", name, err)
+	}
+	if nb.Format < 4 {
+		return fmt.Errorf("%s: notebook format %d is not supported", name, nb.Format)
+	}
+	kernel := nb.Metadata.Kernel.Language
+	if kernel == "" {
+		kernel = nb.Metadata.LanguageInfo.Name
+	}
+	if kernel == "" {
+		kernel = "Python"
+	}
+	l, ok := c.Lookup(kernel)
+	if !ok {
+		l = Language{Namer: Namer(kernel)}
+	}
+	f := FileStats{Path: name, Language: "Jupyter (" + l.Name() + ")"}
+	if c.FoldNotebooks {
+		f.Language = l.Name()
+	}
+	f.FileCount = 1
+	for i, cell := range nb.Cells {
+		src, err := source(cell.Source)
+		if err != nil {
+			return fmt.Errorf("%s: cell %d: %s", name, i, err)
+		}
+		switch cell.Type {
+		case "code":
+			sc := l.newScanner(&f.Stats, &c.Options)
+			sc.scan(src)
+			sc.flush(src)
+		case "markdown":
+			for _, line := range cellLines(src) {
+				f.TotalLines++
+				f.lineLength(utf8.RuneCount(line))
+				if len(bytes.TrimSpace(line)) == 0 {
+					f.BlankLines++
+				} else {
+					f.CommentLines++
+				}
+			}
+		case "raw":
+			if c.Options.RawCellsAsBlank {
+				lines := cellLines(src)
+				for _, line := range lines {
+					f.lineLength(utf8.RuneCount(line))
+				}
+				f.TotalLines += len(lines)
+				f.BlankLines += len(lines)
+			}
+		}
+	}
+	c.add(f)
+	return nil
+}
+
+// cellLines splits the text of a cell into lines.
+func cellLines(src []byte) [][]byte {
+	var lines [][]byte
+	for len(src) > 0 {
+		n := lineLen(src)
+		lines = append(lines, src[:n])
+		if n < len(src) && src[n] == '\r' && n+1 < len(src) && src[n+1] == '\n' {
+			n++
+		}
+		if n < len(src) {
+			n++
+		}
+		src = src[n:]
+	}
+	return lines
+}
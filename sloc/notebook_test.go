@@ -0,0 +1,79 @@
+package sloc
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNotebook(t *testing.T) {
+	tests := []struct {
+		name                        string
+		fold, rawAsBlank            bool
+		lang                        string
+		total, code, comment, blank int
+	}{
+		{"default", false, false, "Jupyter (Python)", 8, 3, 3, 2},
+		{"folded", true, false, "Python", 8, 3, 3, 2},
+		{"raw cells as blank", false, true, "Jupyter (Python)", 10, 3, 3, 4},
+	}
+	for _, tt := range tests {
+		c := NewCounter()
+		c.FoldNotebooks = tt.fold
+		c.Options.RawCellsAsBlank = tt.rawAsBlank
+		if err := c.CountFile(filepath.Join("testdata", "analysis.ipynb")); err != nil {
+			t.Fatal(err)
+		}
+		r := c.Results()
+		s, ok := r[tt.lang]
+		if !ok || len(r) != 1 {
+			t.Errorf("%s: counted as %v, not %s", tt.name, r, tt.lang)
+			continue
+		}
+		if s.TotalLines != tt.total || s.CodeLines != tt.code || s.CommentLines != tt.comment || s.BlankLines != tt.blank {
+			t.Errorf("%s: got %d total, %d code, %d comment, %d blank; want %d, %d, %d, %d",
+				tt.name, s.TotalLines, s.CodeLines, s.CommentLines, s.BlankLines,
+				tt.total, tt.code, tt.comment, tt.blank)
+		}
+	}
+}
+
+func TestNotebookKernel(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`{"nbformat": 4, "cells": []}`, "Jupyter (Python)"},
+		{`{"nbformat": 4, "metadata": {"kernelspec": {"language": "R"}}, "cells": []}`, "Jupyter (R)"},
+		{`{"nbformat": 4, "metadata": {"language_info": {"name": "julia"}}, "cells": []}`, "Jupyter (Julia)"},
+		{`{"nbformat": 4, "metadata": {"language_info": {"name": "sos"}}, "cells": []}`, "Jupyter (sos)"},
+	}
+	for _, tt := range tests {
+		c := NewCounter()
+		if err := c.CountReader("a.ipynb", strings.NewReader(tt.content)); err != nil {
+			t.Errorf("%s: %v", tt.content, err)
+			continue
+		}
+		if r := c.Results(); r[tt.want].FileCount != 1 {
+			t.Errorf("%s: counted as %v, want %s", tt.content, r, tt.want)
+		}
+	}
+}
+
+func TestBadNotebook(t *testing.T) {
+	for _, content := range []string{
+		"",
+		"not json",
+		`{"nbformat": 4, "cells": [`,
+		`{"nbformat": 3, "worksheets": [{"cells": []}]}`,
+		`{"nbformat": 4, "cells": [{"cell_type": "code", "source": 1}]}`,
+	} {
+		c := NewCounter()
+		if err := c.CountReader("a.ipynb", strings.NewReader(content)); err == nil {
+			t.Errorf("%q: no error", content)
+		}
+		if r := c.Results(); len(r) != 0 {
+			t.Errorf("%q: counted as %v", content, r)
+		}
+	}
+}
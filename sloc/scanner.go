@@ -0,0 +1,830 @@
+package sloc
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Quote describes a string literal, inside which comment markers are
+// not recognized.
+type Quote struct {
+	Start     string
+	End       string
+	Escape    byte // zero if the literal has no escape character
+	Multiline bool
+	Doc       bool // counts as a comment when it starts a statement
+	Comment   bool // is really a comment, with a delimiter decided by Open
+	LineStart bool // the end only counts at the very start of a line
+	Operand   bool // only starts where an operand can, not after a name or a ")"
+
+	// Escapes are sequences that stand for a character inside the
+	// literal, where its escape character alone is not enough, as '''
+	// does for '' in Nix.
+	Escapes []string
+
+	// Open, if set, is called where Start matches to recognize literals
+	// whose end depends on how they start. It returns the length of the
+	// opening delimiter and the closing one, or an empty closing delimiter
+	// if it consumed the whole literal. It returns zero if there is no
+	// literal after all.
+	Open func(c []byte) (n int, end string)
+}
+
+var (
+	dquote   = Quote{Start: `"`, End: `"`, Escape: '\\'}
+	squote   = Quote{Start: `'`, End: `'`, Escape: '\\'}
+	backtick = Quote{Start: "`", End: "`", Multiline: true}
+
+	cQuotes  = []Quote{dquote, squote}
+	dqQuotes = []Quote{dquote}
+	goQuotes = []Quote{dquote, squote, backtick}
+	jsQuotes = []Quote{dquote, squote, {Start: "`", End: "`", Escape: '\\', Multiline: true}}
+	shQuotes = []Quote{dquote, {Start: `'`, End: `'`}}
+	pyQuotes = []Quote{
+		{Start: `"""`, End: `"""`, Escape: '\\', Multiline: true, Doc: true},
+		{Start: `'''`, End: `'''`, Escape: '\\', Multiline: true, Doc: true},
+		dquote, squote,
+	}
+	gqlQuotes = []Quote{{Start: `"""`, End: `"""`, Escape: '\\', Multiline: true, Doc: true}, dquote}
+	jlQuotes  = []Quote{
+		{Start: `"""`, End: `"""`, Escape: '\\', Multiline: true, Doc: true},
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+		{Start: `'`, Open: charLiteral},
+	}
+	erlangQuotes = []Quote{{Start: "$", Open: erlangChar}, dquote, squote}
+	prologQuotes = []Quote{{Start: "0'", Open: prologChar}, dquote, squote}
+	rakuQuotes   = []Quote{
+		{Start: "#`", Multiline: true, Comment: true, Open: rakuEmbedded},
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+		{Start: `'`, End: `'`, Escape: '\\', Multiline: true},
+	}
+	exQuotes = []Quote{
+		{Start: "~", Multiline: true, Open: elixirSigil},
+		{Start: "?", Open: elixirChar},
+		{Start: `"""`, End: `"""`, Escape: '\\', Multiline: true},
+		{Start: `'''`, End: `'''`, Escape: '\\', Multiline: true},
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+		{Start: `'`, End: `'`, Escape: '\\', Multiline: true},
+	}
+	luaQuotes = []Quote{
+		{Start: "--[", Multiline: true, Comment: true, Open: luaLongBracket},
+		{Start: "[", Multiline: true, Open: luaLongBracket},
+		dquote, squote,
+	}
+	mlQuotes = []Quote{{Start: `'`, Open: charLiteral}, dquote}
+	fsQuotes = []Quote{
+		{Start: `"""`, End: `"""`, Multiline: true},
+		{Start: `@"`, End: `"`, Multiline: true},
+		{Start: `'`, Open: charLiteral},
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+	}
+	elmQuotes = []Quote{
+		{Start: `"""`, End: `"""`, Escape: '\\', Multiline: true},
+		{Start: `'`, Open: charLiteral},
+		dquote,
+	}
+	zigQuotes = []Quote{{Start: `\\`, Open: restOfLine}, dquote, {Start: `'`, Open: charLiteral}}
+	nimQuotes = []Quote{{Start: `"""`, End: `"""`, Multiline: true}, dquote, {Start: `'`, Open: charLiteral}}
+	vQuotes   = []Quote{
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+		{Start: `'`, End: `'`, Escape: '\\', Multiline: true},
+		{Start: "`", End: "`", Escape: '\\'},
+	}
+	lispQuotes = []Quote{
+		{Start: `#\`, Open: lispChar},
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+	}
+	cljQuotes = []Quote{
+		{Start: `\`, Open: prefixedChar},
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+	}
+	groovyQuotes = []Quote{
+		{Start: `"""`, End: `"""`, Escape: '\\', Multiline: true},
+		{Start: `'''`, End: `'''`, Escape: '\\', Multiline: true},
+		{Start: `$/`, End: `/$`, Multiline: true, Escapes: []string{`$$`, `$/`}},
+		{Start: `/`, Escape: '\\', Multiline: true, Operand: true, Open: slashyString},
+		dquote, squote,
+	}
+	eiffelQuotes = []Quote{
+		{Start: `"[`, End: `]"`, Multiline: true},
+		{Start: `"{`, End: `}"`, Multiline: true},
+		{Start: `"`, End: `"`, Escape: '%'},
+		{Start: `'`, End: `'`, Escape: '%'},
+	}
+	awkQuotes = []Quote{dquote, {Start: `/`, Escape: '\\', Operand: true, Open: slashyString}}
+	rsQuotes  = []Quote{
+		{Start: "r", Multiline: true, Open: rustRawString},
+		{Start: `'`, Open: charLiteral},
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+	}
+	ktQuotes    = []Quote{{Start: `"""`, End: `"""`, Multiline: true}, dquote, squote}
+	swiftQuotes = []Quote{
+		{Start: "#", Multiline: true, Open: swiftRawString},
+		{Start: `"""`, End: `"""`, Escape: '\\', Multiline: true},
+		dquote,
+	}
+	dlangQuotes = []Quote{
+		{Start: `r"`, End: `"`, Multiline: true},
+		backtick,
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+		{Start: `'`, Open: charLiteral},
+	}
+	psQuotes = []Quote{
+		{Start: `@"`, End: `"@`, Multiline: true, LineStart: true},
+		{Start: `@'`, End: `'@`, Multiline: true, LineStart: true},
+		{Start: `"`, End: `"`, Escape: '`', Multiline: true},
+		{Start: `'`, End: `'`, Multiline: true},
+	}
+	nixQuotes = []Quote{
+		{Start: `''`, End: `''`, Multiline: true, Escapes: []string{`'''`, `''$`, `''\`}},
+		{Start: `"`, End: `"`, Escape: '\\', Multiline: true},
+	}
+	abapQuotes = []Quote{plainQuotes[1], {Start: "`", End: "`"}, {Start: "|", End: "|", Escape: '\\'}}
+	elQuotes   = []Quote{{Start: "?", Open: erlangChar}, {Start: `"`, End: `"`, Escape: '\\', Multiline: true}}
+	vimQuotes  = []Quote{{Start: `"`, Comment: true, Open: vimComment}, dquote, {Start: `'`, End: `'`}}
+	stQuotes   = []Quote{{Start: "$", Open: prefixedChar}, plainQuotes[1]}
+	texQuotes  = []Quote{{Start: `\`, Open: prefixedChar}}
+	tomlQuotes = []Quote{
+		{Start: `"""`, End: `"""`, Escape: '\\', Multiline: true},
+		{Start: `'''`, End: `'''`, Multiline: true},
+		dquote,
+		{Start: `'`, End: `'`},
+	}
+
+	// Fortran, COBOL, Basic, Pascal, Modula-2, Ada, VHDL and Coq double
+	// quotes inside strings rather than escaping them.
+	plainQuotes  = []Quote{{Start: `"`, End: `"`}, {Start: `'`, End: `'`}}
+	plainDQuotes = plainQuotes[:1]
+	plainSQuotes = plainQuotes[1:]
+	vhdlQuotes   = []Quote{{Start: `'`, Open: charLiteral}, plainQuotes[0]}
+
+	// Unquoted URLs such as url(//cdn.example.com/x.png) hold no
+	// comments either.
+	scssQuotes = []Quote{dquote, squote, {Start: "url(", End: ")"}}
+)
+
+// luaLongBracket recognizes Lua's long brackets such as [[, [==[ and so
+// on, optionally preceded by the "--" of a comment. They close with a
+// bracket of the same level.
+func luaLongBracket(c []byte) (int, string) {
+	n := 0
+	for n < len(c) && c[n] == '-' {
+		n++
+	}
+	n++
+	level := 0
+	for n < len(c) && c[n] == '=' {
+		n, level = n+1, level+1
+	}
+	if n >= len(c) || c[n] != '[' {
+		return 0, ""
+	}
+	return n + 1, "]" + strings.Repeat("=", level) + "]"
+}
+
+// rustRawString recognizes r"...", r#"..."# and so on.
+func rustRawString(c []byte) (int, string) {
+	n := 1
+	for n < len(c) && c[n] == '#' {
+		n++
+	}
+	if n >= len(c) || c[n] != '"' {
+		return 0, ""
+	}
+	return n + 1, `"` + string(c[1:n])
+}
+
+// swiftRawString recognizes #"..."#, #"""..."""# and so on, with any
+// number of #.
+func swiftRawString(c []byte) (int, string) {
+	n := 0
+	for n < len(c) && c[n] == '#' {
+		n++
+	}
+	q := `"`
+	if hasMarker(c[n:], `"""`) {
+		q = `"""`
+	} else if !hasMarker(c[n:], q) {
+		return 0, ""
+	}
+	return n + len(q), q + string(c[:n])
+}
+
+// elixirSigil recognizes sigils such as ~r/.../, ~S"""...""" and
+// ~w(...), whose delimiters are one of a few pairs.
+func elixirSigil(c []byte) (int, string) {
+	n := 1
+	for n < len(c) && isLetter(c[n]) {
+		n++
+	}
+	if n == 1 || n >= len(c) {
+		return 0, ""
+	}
+	for _, d := range []string{`"""`, `'''`} {
+		if hasMarker(c[n:], d) {
+			return n + len(d), d
+		}
+	}
+	if i := strings.IndexByte(`"'/|([{<`, c[n]); i >= 0 {
+		return n + 1, string(`"'/|)]}>`[i])
+	}
+	return 0, ""
+}
+
+// elixirChar consumes the character codes ?#, ?" and ?', leaving the ? at
+// the end of names such as empty? alone.
+func elixirChar(c []byte) (int, string) {
+	if len(c) >= 2 && strings.IndexByte(`#"'`, c[1]) >= 0 {
+		return 2, ""
+	}
+	return 0, ""
+}
+
+// erlangChar consumes character codes such as $% and $\", which are
+// code, and likewise Emacs Lisp's ?; and ?\".
+func erlangChar(c []byte) (int, string) {
+	switch {
+	case len(c) >= 3 && c[1] == '\\' && !isEOL(c[2]):
+		return 3, ""
+	case len(c) >= 2 && !isEOL(c[1]):
+		return 2, ""
+	}
+	return 0, ""
+}
+
+// slashyString recognizes Groovy's /.../ strings and AWK's regular
+// expressions, leaving // comments alone.
+func slashyString(c []byte) (int, string) {
+	if len(c) < 2 || c[1] == '/' || isEOL(c[1]) {
+		return 0, ""
+	}
+	return 1, "/"
+}
+
+// restOfLine consumes the rest of the line, as the lines of Zig's
+// multiline strings run from their \\ to the end.
+func restOfLine(c []byte) (int, string) {
+	return lineLen(c), ""
+}
+
+// prologChar consumes character codes such as 0'% and 0'\n, which are
+// code.
+func prologChar(c []byte) (int, string) {
+	switch {
+	case len(c) >= 4 && c[2] == '\\' && !isEOL(c[3]):
+		return 4, ""
+	case len(c) >= 3 && !isEOL(c[2]):
+		return 3, ""
+	}
+	return 0, ""
+}
+
+// rakuEmbedded recognizes Raku's embedded comments, such as #`( ... ) and
+// #`{{ ... }}, which close with as many of the matching bracket.
+func rakuEmbedded(c []byte) (int, string) {
+	n := 2
+	if n >= len(c) {
+		return 0, ""
+	}
+	i := strings.IndexByte("([{<", c[n])
+	if i < 0 {
+		return 0, ""
+	}
+	for n < len(c) && c[n] == c[2] {
+		n++
+	}
+	return n, strings.Repeat(string(")]}>"[i]), n-2)
+}
+
+// vimComment consumes the rest of the line from a " that no other closes,
+// which makes it a comment rather than the start of a string.
+func vimComment(c []byte) (int, string) {
+	n := lineLen(c)
+	for i := 1; i < n; i++ {
+		switch c[i] {
+		case '\\':
+			i++
+		case '"':
+			return 0, ""
+		}
+	}
+	return n, ""
+}
+
+// prefixedChar consumes a character along with the one that makes it
+// literal, as in TeX's control symbols \% and \\, Clojure's characters \;
+// and \" and Smalltalk's $" and $', so that they start neither comments
+// nor strings.
+func prefixedChar(c []byte) (int, string) {
+	if len(c) >= 2 && !isEOL(c[1]) {
+		return 2, ""
+	}
+	return 0, ""
+}
+
+// lispChar consumes characters such as #\; and #\", which start neither
+// comments nor strings.
+func lispChar(c []byte) (int, string) {
+	if len(c) >= 3 && !isEOL(c[2]) {
+		return 3, ""
+	}
+	return 0, ""
+}
+
+// charLiteral consumes character literals such as '"' and '\”, leaving
+// lifetimes and type variables such as 'a alone.
+func charLiteral(c []byte) (int, string) {
+	if len(c) >= 4 && c[1] == '\\' {
+		if i := bytes.IndexByte(c[3:], '\''); i >= 0 && i < 10 {
+			return i + 4, ""
+		}
+	}
+	if len(c) >= 3 && !isEOL(c[1]) && c[2] == '\'' {
+		return 3, ""
+	}
+	return 0, ""
+}
+
+// A scanner classifies the lines of a file as code, comment or blank,
+// tracking block comments and string literals across lines.
+type scanner struct {
+	Commenter
+	s *Stats
+
+	block       *Block // the block comment being scanned, if any
+	depth       int    // its nesting depth
+	quote       *Quote // the string literal being scanned, if any
+	end         string // the delimiter that ends quote
+	doc         bool   // whether quote counts as a comment
+	docBlock    bool   // whether block or quote is documentation
+	lineComment bool
+	parens      int  // bracket nesting depth
+	last        byte // the last code byte on the line
+	cont        bool // whether the previous line ended with a backslash
+	lines       LineFunc
+	mixed       Mixed
+	tags        []string
+	tagLine     func(tag string, line int)
+	lineClass   func(c Class)
+	lineNo      int          // the lines ended so far
+	runes       int          // the length of the current line
+	heredocs    []heredocEnd // the here documents started on this line
+	heredoc     []heredocEnd // the here documents being scanned
+	region      *Region      // the embedded region being scanned, if any
+	outer       Commenter    // the commenter to return to at its end
+
+	// what the current line has held so far
+	code, comment, skip bool
+	lead                bool // whether only blanks precede the position
+	docLine             bool // whether documentation is on it
+}
+
+func hasMarker(c []byte, m string) bool {
+	return len(m) > 0 && len(c) >= len(m) && string(c[:len(m)]) == m
+}
+
+// hasLeadMarker is like hasMarker, but ignores case and, for markers
+// ending in a letter, requires a word to end with them.
+func hasLeadMarker(c []byte, m string) bool {
+	n := len(m)
+	if n == 0 || len(c) < n || !bytes.EqualFold(c[:n], []byte(m)) {
+		return false
+	}
+	return !isLetter(m[n-1]) || len(c) == n || !isWordByte(c[n])
+}
+
+// escapeLen returns the length of the escape sequence from escapes that
+// starts c, or zero.
+func escapeLen(c []byte, escapes []string) int {
+	for _, e := range escapes {
+		if hasMarker(c, e) {
+			return len(e)
+		}
+	}
+	return 0
+}
+
+// isEOL reports whether b ends a line. Lines end in "\n", "\r\n" or, in
+// old Mac files, just "\r".
+func isEOL(b byte) bool {
+	return b == '\n' || b == '\r'
+}
+
+// lineLen returns the length of the line starting c, without its end.
+func lineLen(c []byte) int {
+	if i := bytes.IndexAny(c, "\r\n"); i >= 0 {
+		return i
+	}
+	return len(c)
+}
+
+func isBlank(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r'
+}
+
+// blankAt returns the length of the character starting c and whether it
+// is white space, such as a no-break space. The scanner otherwise works on
+// bytes, which is safe for UTF-8 since the markers it looks for are ASCII
+// and no byte of a multi-byte character is.
+func blankAt(c []byte) (int, bool) {
+	if c[0] < utf8.RuneSelf {
+		return 1, isBlank(c[0])
+	}
+	r, n := utf8.DecodeRune(c)
+	return n, unicode.IsSpace(r)
+}
+
+// endLine accounts for the line just finished. A line with both code and
+// a comment on it counts as the mixed policy says.
+func (sc *scanner) endLine() {
+	sc.lineNo++
+	if sc.skip {
+		sc.skip = false
+		sc.classify(Skip)
+		return
+	}
+	sc.s.TotalLines++
+	sc.s.lineLength(sc.runes)
+	comment := sc.comment || sc.depth > 0
+	switch {
+	case sc.code && comment:
+		if sc.mixed != MixedComment {
+			sc.s.CodeLines++
+			sc.classify(Code)
+		} else {
+			sc.classify(Comment)
+		}
+		if sc.mixed != MixedCode {
+			sc.commentLine()
+		}
+	case sc.code:
+		sc.s.CodeLines++
+		sc.classify(Code)
+	case comment:
+		sc.commentLine()
+		sc.classify(Comment)
+	default:
+		sc.s.BlankLines++
+		sc.classify(Blank)
+	}
+	sc.lineComment = false
+	sc.heredoc, sc.heredocs = append(sc.heredoc, sc.heredocs...), nil
+	if sc.quote != nil && !sc.quote.Multiline {
+		sc.quote = nil
+	}
+	sc.cont, sc.last = sc.last == '\\', 0
+	sc.code, sc.comment = false, sc.depth > 0 || (sc.quote != nil && sc.doc)
+	sc.docLine = sc.docBlock && sc.comment
+	sc.lead = true
+}
+
+// classify reports what the line ended counts as to lineClass, if set.
+func (sc *scanner) classify(c Class) {
+	if sc.lineClass != nil {
+		sc.lineClass(c)
+	}
+}
+
+// commentLine counts a comment line, and whether it is documentation.
+func (sc *scanner) commentLine() {
+	sc.s.CommentLines++
+	if sc.docLine {
+		sc.s.DocLines++
+	}
+}
+
+// prevByte returns the byte before c[i], or zero at the start of c.
+func prevByte(c []byte, i int) byte {
+	if i == 0 {
+		return 0
+	}
+	return c[i-1]
+}
+
+// tagAt returns the tag that starts c as a word, where prev is the byte
+// before c, or "".
+func (sc *scanner) tagAt(c []byte, prev byte) string {
+	if len(sc.tags) == 0 || isWordByte(prev) {
+		return ""
+	}
+	for _, t := range sc.tags {
+		if hasMarker(c, t) && (len(c) == len(t) || !isWordByte(c[len(t)])) {
+			return t
+		}
+	}
+	return ""
+}
+
+// addTag counts the tag found on the current line.
+func (sc *scanner) addTag(tag string) {
+	if sc.s.Tags == nil {
+		sc.s.Tags = map[string]int{}
+	}
+	sc.s.Tags[tag]++
+	if sc.tagLine != nil {
+		sc.tagLine(tag, sc.lineNo+1)
+	}
+}
+
+// findTags counts the tags in the comment text c, where prev is the byte
+// before it.
+func (sc *scanner) findTags(c []byte, prev byte) {
+	for i := range c {
+		if t := sc.tagAt(c[i:], prev); t != "" {
+			sc.addTag(t)
+		}
+		prev = c[i]
+	}
+}
+
+// docMarker reports whether the comment starting c is documentation.
+func (sc *scanner) docMarker(c []byte) bool {
+	for _, m := range sc.DocComments {
+		if hasMarker(c, m) {
+			n := len(m)
+			return len(c) == n || (c[n] != m[n-1] && c[n] != '/')
+		}
+	}
+	return false
+}
+
+func (sc *scanner) scan(c []byte) {
+	bol := true
+	for i := 0; i < len(c); {
+		rest := c[i:]
+		if bol {
+			sc.runes = utf8.RuneCount(rest[:lineLen(rest)])
+		}
+		if bol && len(sc.heredoc) > 0 {
+			bol = false
+			i += sc.heredocLine(rest)
+			continue
+		}
+		if bol && sc.lines != nil && sc.depth == 0 && sc.quote == nil {
+			bol = false
+			if n, ok := sc.line(rest); ok {
+				i += n
+				continue
+			}
+		}
+		b := c[i]
+		if isEOL(b) {
+			sc.endLine()
+			bol = true
+			i++
+			if b == '\r' && i < len(c) && c[i] == '\n' {
+				i++
+			}
+			continue
+		}
+		bol = false
+		lead := sc.lead
+		if _, blank := blankAt(rest); !blank {
+			sc.lead = false
+		}
+		switch {
+		case sc.lineComment:
+			// Nothing ends a line comment but the end of the line, or
+			// that of the region.
+			n := lineLen(rest)
+			if sc.region != nil {
+				if j := bytes.Index(rest[:n], []byte(sc.region.End)); j >= 0 {
+					n = j
+					sc.lineComment = false
+				}
+			}
+			if len(sc.tags) > 0 {
+				sc.findTags(rest[:n], prevByte(c, i))
+			}
+			i += n
+		case sc.depth > 0:
+			n := 0
+			if sc.block.Nesting {
+				n = sc.blockMarker(rest, sc.block.Start, lead)
+			}
+			if n > 0 {
+				sc.depth++
+			} else if n = sc.blockMarker(rest, sc.block.End, lead); n > 0 {
+				sc.depth--
+			} else {
+				n = 1
+				if t := sc.tagAt(rest, prevByte(c, i)); t != "" {
+					sc.addTag(t)
+				}
+			}
+			i += n
+		case sc.quote != nil:
+			if sc.doc {
+				sc.comment = true
+				if t := sc.tagAt(rest, prevByte(c, i)); t != "" {
+					sc.addTag(t)
+				}
+			} else {
+				sc.code = true
+			}
+			if n := escapeLen(rest, sc.quote.Escapes); n > 0 {
+				i += n
+				continue
+			}
+			switch {
+			case sc.quote.Escape != 0 && b == sc.quote.Escape:
+				i++
+				if i < len(c) && !isEOL(c[i]) {
+					i++
+				}
+			case hasMarker(rest, sc.end) && (!sc.quote.LineStart || i == 0 || isEOL(c[i-1])):
+				i += len(sc.end)
+				sc.quote = nil
+			default:
+				i++
+			}
+		default:
+			i += sc.scanCode(rest, lead)
+		}
+	}
+}
+
+// heredocLine accounts for the line starting c, inside a here document,
+// and returns its length.
+func (sc *scanner) heredocLine(c []byte) int {
+	n := lineLen(c)
+	line := c[:n]
+	if len(bytes.TrimSpace(line)) > 0 {
+		sc.code = true
+	}
+	if sc.heredoc[0].closes(line) {
+		sc.heredoc = sc.heredoc[1:]
+	}
+	return n
+}
+
+// line offers the line starting c to the language's LineFunc, and returns
+// how many bytes to skip if it claimed the line.
+func (sc *scanner) line(c []byte) (int, bool) {
+	n := lineLen(c)
+	switch sc.lines(c[:n]) {
+	case Code:
+		sc.code = true
+	case Comment:
+		sc.comment = true
+		if len(sc.tags) > 0 {
+			sc.findTags(c[:n], 0)
+		}
+	case Blank:
+	case Skip:
+		sc.skip = true
+	default:
+		return 0, false
+	}
+	return n, true
+}
+
+// blockMarker returns the length of the block comment marker m at the
+// start of c, or zero if there is none, where lead tells whether only
+// blanks precede c on its line.
+func (sc *scanner) blockMarker(c []byte, m string, lead bool) int {
+	if !lead && sc.LineStart {
+		return 0
+	}
+	if strings.IndexByte(m, ' ') >= 0 {
+		return tagMarkerLen(c, m)
+	}
+	if hasMarker(c, m) {
+		return len(m)
+	}
+	return 0
+}
+
+// tagMarkerLen returns the length of the marker m at the start of c, or
+// zero if there is none, where a space in m stands for any run of blanks,
+// and of the dashes of templates' whitespace control, including none. So
+// "{% comment %}" also matches "{%- comment -%}".
+func tagMarkerLen(c []byte, m string) int {
+	n := 0
+	for i := 0; i < len(m); i++ {
+		if m[i] == ' ' {
+			for n < len(c) && (c[n] == ' ' || c[n] == '\t' || c[n] == '-') {
+				n++
+			}
+			continue
+		}
+		if n >= len(c) || c[n] != m[i] {
+			return 0
+		}
+		n++
+	}
+	return n
+}
+
+// scanCode handles the start of c outside of any comment or string, and
+// returns how many bytes it consumed.
+func (sc *scanner) scanCode(c []byte, lead bool) int {
+	if r := sc.region; r != nil && hasMarker(c, r.End) {
+		sc.Commenter, sc.region, sc.code = sc.outer, nil, true
+		return len(r.End)
+	}
+	for i := range sc.Regions {
+		if r := &sc.Regions[i]; hasMarker(c, r.Start) {
+			sc.outer, sc.Commenter, sc.region, sc.code = sc.Commenter, *r.Commenter, r, true
+			return len(r.Start)
+		}
+	}
+	for _, op := range sc.Operators {
+		if hasMarker(c, op) {
+			sc.code = true
+			return len(op)
+		}
+	}
+	for i := range sc.Blocks {
+		b := &sc.Blocks[i]
+		if n := sc.blockMarker(c, b.Start, lead); n > 0 {
+			sc.block, sc.depth, sc.comment = b, 1, true
+			sc.docBlock = sc.docMarker(c)
+			sc.docLine = sc.docLine || sc.docBlock
+			return n
+		}
+	}
+	for i := range sc.LeadBlocks {
+		if b := &sc.LeadBlocks[i]; lead && hasMarker(c, b.Start) {
+			sc.block, sc.depth, sc.comment, sc.docBlock = b, 1, true, false
+			return len(b.Start)
+		}
+	}
+	// A marker right after its own first character is the tail of
+	// something else, such as the here string <<< of shells.
+	if h := sc.Heredoc; h != nil && sc.last != h.Start[0] {
+		if n, e := h.open(c); n > 0 {
+			sc.heredocs = append(sc.heredocs, e)
+			sc.code = true
+			return n
+		}
+	}
+	for i := range sc.Strings {
+		q := &sc.Strings[i]
+		if !hasMarker(c, q.Start) || (q.Operand && !operandNext(sc.last)) {
+			continue
+		}
+		n, end := len(q.Start), q.End
+		if q.Open != nil {
+			if n, end = q.Open(c); n == 0 {
+				continue
+			}
+		}
+		sc.doc = q.Comment || (q.Doc && !sc.code && sc.parens == 0 && !sc.cont)
+		sc.docBlock = sc.doc && !q.Comment
+		if sc.doc {
+			sc.comment = true
+			sc.docLine = sc.docLine || sc.docBlock
+		} else {
+			sc.code = true
+		}
+		if end != "" {
+			sc.quote, sc.end = q, end
+		}
+		return n
+	}
+	for _, m := range sc.LineComments {
+		if hasMarker(c, m) {
+			sc.lineComment, sc.comment = true, true
+			sc.docLine = sc.docLine || sc.docMarker(c)
+			return len(m)
+		}
+	}
+	if lead {
+		for _, m := range sc.LeadComments {
+			if hasLeadMarker(c, m) {
+				sc.lineComment, sc.comment = true, true
+				return len(m)
+			}
+		}
+	}
+	switch b := c[0]; b {
+	case '(', '[', '{':
+		sc.parens++
+	case ')', ']', '}':
+		if sc.parens > 0 {
+			sc.parens--
+		}
+	}
+	n, blank := blankAt(c)
+	if !blank {
+		sc.code, sc.last = true, c[0]
+	}
+	return n
+}
+
+// operandNext reports whether an operand can follow last, the last code
+// byte on the line, rather than it ending one, as names, numbers and
+// closing brackets do. The / of a division follows those.
+func operandNext(last byte) bool {
+	return last == 0 || !(isWordByte(last) || strings.IndexByte(")]}", last) >= 0)
+}
+
+// flush accounts for a final line that lacks a trailing newline.
+func (sc *scanner) flush(c []byte) {
+	if len(c) > 0 && !isEOL(c[len(c)-1]) {
+		sc.endLine()
+	}
+}
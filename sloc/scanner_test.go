@@ -0,0 +1,163 @@
+package sloc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		lang, fname   string
+		content       string
+		code, comment int
+	}{
+		{"Go", "a.go", "url := \"http://example.com\"\n", 1, 0},
+		{"Go", "a.go", "s := \"/* not a comment */\"\nx := 1\n", 2, 0},
+		{"Go", "a.go", "s := \"*/\"\n", 1, 0},
+		{"Go", "a.go", "s := \"say \\\"//\\\" twice\"\n", 1, 0},
+		{"Go", "a.go", "s := \"\\\\\" // a backslash\n", 1, 0},
+		{"Go", "a.go", "s := `C:\\` // raw strings have no escapes\n", 1, 0},
+		{"Go", "a.go", "s := `\n// not a comment\n/* nor this\n`\n", 4, 0},
+		{"Go", "a.go", "/* a \"quote\n*/\nx := 1\n", 1, 2},
+		{"C", "a.c", "char c = '\"'; // a quote\n", 1, 0},
+		{"C", "a.c", "char *s = \"a\\\"b\"; /* c */\n", 1, 0},
+		{"JavaScript", "a.js", "let s = `a\n// b\n`;\n", 3, 0},
+		{"Python", "a.py", "\"\"\"A module.\n\nMore.\n\"\"\"\n", 0, 4},
+		{"Python", "a.py", "'''A module.'''\nx = 1\n", 1, 1},
+		{"Python", "a.py", "SQL = \"\"\"\nselect 1\n\"\"\"\n", 3, 0},
+		{"Python", "a.py", "f(\n    '''not a docstring''')\n", 2, 0},
+		{"Python", "a.py", "s = 'a' \\\n    '''not a docstring'''\n", 2, 0},
+		{"Python", "a.py", "s = \"# not a comment\"\n", 1, 0},
+		{"Python", "a.py", "s = '\\'' # a quote\n", 1, 0},
+		{"Rust", "a.rs", "/* a /* b */\nc */\nlet x = 1;\n", 1, 2},
+		{"Rust", "a.rs", "let s = r##\"a \"# /* b\n\"##;\n", 2, 0},
+		{"Rust", "a.rs", "let c = '\"'; let s = \"/*\";\n", 1, 0},
+		{"Rust", "a.rs", "let c = '\\''; let s = \"/*\";\n", 1, 0},
+		{"Rust", "a.rs", "fn f<'a>(s: &'a str) {}\n", 1, 0},
+		{"Scala", "a.scala", "/* a /* b */\nc */\n", 1, 1},
+		{"Ruby", "a.rb", "x = 1 =begin\ny = 2\n", 2, 0},
+		{"Ruby", "a.rb", "=begin\nx =end\n=end\ny = 2\n", 1, 3},
+		{"Ruby", "a.rb", "s = <<~EOS\n=end\nEOS\n", 3, 0},
+		{"MATLAB", "a.m", "  %{\nhelp\n  %}\nx = 1\n", 1, 3},
+		{"MATLAB", "a.m", "s = \"%{\"\nx = 1\n", 2, 0},
+		{"CoffeeScript", "a.coffee", "###\nblock\n###\nx = 1\n", 1, 3},
+		{"CoffeeScript", "a.coffee", "s = '###'\nx = 1\n", 2, 0},
+		{"Lua", "a.lua", "--[==[\n]]\n]==]\nx = 1\n", 1, 3},
+		{"Lua", "a.lua", "s = [[\n--[[\n]]\n", 3, 0},
+		{"Lua", "a.lua", "-- [[ just a line\nx = 1\n", 1, 1},
+		{"Lua", "a.lua", "x = a[1]\ny = 2\n", 2, 0},
+		{"Lua", "a.lua", "s = \"--[[\"\ny = 2\n", 2, 0},
+		{"ML", "a.ml", "let c = '\\000'\n", 1, 0},
+		{"ML", "a.ml", "let s = \"\\000\"\n", 1, 0},
+		{"ML", "a.ml", "(* a (* b *)\nc *)\nlet x = 1\n", 1, 2},
+		{"ML", "a.ml", "let s = \"(*\"\nlet x = 1\n", 2, 0},
+		{"ML", "a.ml", "let c = '\"'\nlet x = 1\n", 2, 0},
+		{"ML", "a.ml", "let f (x : 'a) = x\n", 1, 0},
+		{"PHP", "a.php", "<?php\n# a\n// b\n$x = '#';\n", 2, 2},
+		{"PHP", "a.php", "<p># // text</p>\n<!-- a -->\n", 1, 1},
+		{"PHP", "a.php", "<?php // a ?> <p>b</p>\n", 1, 0},
+		{"PHP", "a.php", "<?php /* a ?> b */ ?>\n", 1, 0},
+		{"Vue", "a.vue", "<template>\n<!-- <script> // a -->\n</template>\n", 2, 1},
+		{"Vue", "a.vue", "<style>\n/* a */\n</style>\n<style>\n// b\n</style>\n", 5, 1},
+		{"Vue", "a.vue", "<script setup>\n<!-- a -->\n</script>\n<!-- b -->\n", 3, 1},
+		{"Svelte", "a.svelte", "<script lang=\"ts\">\n/* a\n*/\n</script>\n", 2, 2},
+		{"Fortran", "a.f90", "C = 1\n  ! a\n", 1, 1},
+		{"Fortran", "a.f", "C = 1\n      X = 1 ! a\n     +  ! b\n", 2, 1},
+		{"Fortran", "a.f", "*\n     0  ! a\n\tX = '!'\n", 2, 1},
+		{"Batch", "a.bat", "REM\nRem.\nremark\n", 1, 2},
+		{"Batch", "a.cmd", "echo rem a\n@REM b\n:label\n", 2, 1},
+		{"D", "a.d", "/* a /+ b */\nx = 1; +/\n", 1, 1},
+		{"D", "a.d", "/+ a /* b +/\nx = 1; */\n", 1, 1},
+		{"Pascal", "a.pas", "{$IFDEF X}\n(*$I a.inc*)\n{ a }\n", 2, 1},
+		{"Pascal", "a.pas", "(* a { b *)\nx := '}';\n", 1, 1},
+		{"Liquid", "a.liquid", "{%comment%}\na\n{%   endcomment %}\nb\n", 1, 3},
+		{"Liquid", "a.liquid", "{% commentary %}\n", 1, 0},
+		{"Handlebars", "a.hbs", "{{!-- a }} b --}}\nc\n", 1, 1},
+		{"Elm", "a.elm", "{- a {- b -}\nc -}\nx = 1\n", 1, 2},
+		{"F#", "a.fs", "let f = (*)\nlet g = ( * )\n", 2, 0},
+		{"Groovy", "a.groovy", "x = /* a */ 1\ny = a /* b */ / 2\n", 2, 0},
+		{"Groovy", "a.groovy", "def r = /a\\/\n// b/\n", 2, 0},
+		{"Groovy", "a.groovy", "def n = (a) / b // c\n/* d */\n", 1, 1},
+		{"SAS", "a.sas", "x = a * b;\n* c\nd;\n", 1, 2},
+		{"SAS", "a.sas", "* a /* b */;\nc;\n", 1, 1},
+		{"Stata", "a.do", "gen x = a * b\n  * c\n", 1, 1},
+		{"ABAP", "a.abap", "* a\n x = y * z. \"b\n", 1, 1},
+		{"ColdFusion", "a.cfm", "<!--- a <!--- b ---> c --->\n<p>d</p>\n", 1, 1},
+		{"Vim script", "a.vim", "let s = \"str\" \" trailing comment\n\" a \"quoted\" word\n", 1, 1},
+		{"Vim script", "a.vim", "let s = \"a \\\" b\"\necho s \" c\n", 2, 0},
+		{"SASS", "a.sass", "// a\nb\n  c: url(//d/e.png)\n", 2, 1},
+		{"SCSS", "a.scss", "a { content: \"/*\"; }\nb { c: d; }\n", 2, 0},
+		{"SQL", "a.sql", "# a\n-- b\nselect 1;\n", 1, 2},
+		{"Shell", "a.sh", "cat <<<x\n# a\n", 1, 1},
+		{"Shell", "a.sh", "cat <<EOF\n  EOF\n# b\nEOF\n", 4, 0},
+		{"Shell", "a.sh", "cat <<-EOF\n# b\n\tEOF\n# c\n", 3, 1},
+		{"Shell", "a.sh", "cat <<A <<B\n# a\nA\n# b\nB\n# c\n", 5, 1},
+		{"Ruby", "a.rb", "x = y <<z\n# a\n", 1, 1},
+		{"Ruby", "a.rb", "s = <<-eos\n# a\n  eos\n# b\n", 3, 1},
+	}
+	for _, tt := range tests {
+		l, _ := language(tt.lang, tt.fname)
+		var s Stats
+		l.Update([]byte(tt.content), &s)
+		if s.CodeLines != tt.code || s.CommentLines != tt.comment {
+			t.Errorf("%s %q: got %d code, %d comment; want %d, %d",
+				tt.lang, tt.content, s.CodeLines, s.CommentLines, tt.code, tt.comment)
+		}
+	}
+}
+
+func TestCommentStyles(t *testing.T) {
+	pascal := Language{Commenter: Commenter{
+		LineComments: []string{`//`},
+		Blocks:       []Block{{`{`, `}`, false}, {`(*`, `*)`, false}},
+		Strings:      []Quote{{Start: `'`, End: `'`}},
+	}}
+	tests := []struct {
+		content       string
+		code, comment int
+	}{
+		{"{ one }\n(* two *)\n// three\nx := 1;\n", 1, 3},
+		{"{ a (* b }\nx := 1;\n", 1, 1},
+		{"(* a { b *)\nx := 1;\n", 1, 1},
+		{"(* a } b\n*)\nx := 1;\n", 1, 2},
+		{"s := '{ not a comment }';\n", 1, 0},
+	}
+	for _, tt := range tests {
+		var s Stats
+		pascal.Update([]byte(tt.content), &s)
+		if s.CodeLines != tt.code || s.CommentLines != tt.comment {
+			t.Errorf("%q: got %d code, %d comment; want %d, %d",
+				tt.content, s.CodeLines, s.CommentLines, tt.code, tt.comment)
+		}
+	}
+}
+
+func TestMixed(t *testing.T) {
+	c, _ := language("C", "a.c")
+	tests := []struct {
+		name    string
+		content string
+		// code and comment lines for MixedCode, MixedComment and MixedBoth
+		counts [3][2]int
+	}{
+		{"trailing comment", "x = 1; // set x\n", [3][2]int{{1, 0}, {0, 1}, {1, 1}}},
+		{"block after code", "x = 1; /* set\nx */\n", [3][2]int{{1, 1}, {0, 2}, {1, 2}}},
+		{"code after block", "/* set\nx */ x = 1;\n", [3][2]int{{1, 1}, {0, 2}, {1, 2}}},
+		{"block inside code", "f(/* x */ 1);\n", [3][2]int{{1, 0}, {0, 1}, {1, 1}}},
+		{"no mixed lines", "// a\nx = 1;\n\n", [3][2]int{{1, 1}, {1, 1}, {1, 1}}},
+	}
+	for _, tt := range tests {
+		for i, m := range []Mixed{MixedCode, MixedComment, MixedBoth} {
+			var s Stats
+			c.UpdateOptions([]byte(tt.content), &s, &Options{Mixed: m})
+			want := tt.counts[i]
+			if s.CodeLines != want[0] || s.CommentLines != want[1] {
+				t.Errorf("%s, policy %d: got %d code, %d comment; want %d, %d",
+					tt.name, m, s.CodeLines, s.CommentLines, want[0], want[1])
+			}
+			if lines := strings.Count(tt.content, "\n"); s.TotalLines != lines {
+				t.Errorf("%s, policy %d: got %d total lines, want %d", tt.name, m, s.TotalLines, lines)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package sloc
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// interpreters maps the name of a script interpreter, without any version
+// suffix, to the language it runs.
+var interpreters = map[string]string{
+	"sh":      "Shell",
+	"dash":    "Shell",
+	"ksh":     "Shell",
+	"zsh":     "Zsh",
+	"csh":     "C Shell",
+	"tcsh":    "C Shell",
+	"fish":    "Fish",
+	"sed":     "sed",
+	"bash":    "Bash",
+	"python":  "Python",
+	"pypy":    "Python",
+	"perl":    "Perl",
+	"raku":    "Raku",
+	"swipl":   "Prolog",
+	"ruby":    "Ruby",
+	"node":    "JavaScript",
+	"nodejs":  "JavaScript",
+	"lua":     "Lua",
+	"luajit":  "Lua",
+	"awk":     "AWK",
+	"gawk":    "AWK",
+	"mawk":    "AWK",
+	"nawk":    "AWK",
+	"tclsh":   "Tcl",
+	"wish":    "Tcl",
+	"Rscript": "R",
+	"php":     "PHP",
+	"escript": "Erlang",
+}
+
+// interpreter returns the interpreter named by a "#!" line, looking past
+// env and its options.
+func interpreter(line string) string {
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	name := path.Base(fields[0])
+	if name == "env" {
+		name = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				name = path.Base(f)
+				break
+			}
+		}
+	}
+	// Strip versions like python3 or python3.11.
+	return strings.TrimRight(name, "0123456789.")
+}
+
+// shebangLanguage looks at the first line of the head of a file for an
+// interpreter it knows.
+func shebangLanguage(head []byte, langs []Language) (Language, bool) {
+	if !bytes.HasPrefix(head, []byte("#!")) || bytes.IndexByte(head, 0) >= 0 {
+		return Language{}, false
+	}
+	head = head[:lineLen(head)]
+	name, ok := interpreters[interpreter(string(head))]
+	if !ok {
+		return Language{}, false
+	}
+	for _, l := range langs {
+		if l.Name() == name {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
@@ -0,0 +1,43 @@
+package sloc
+
+import "testing"
+
+func TestInterpreter(t *testing.T) {
+	tests := []struct {
+		line, want string
+	}{
+		{"#!/bin/sh", "sh"},
+		{"#! /bin/bash -e", "bash"},
+		{"#!/usr/bin/python3.11", "python"},
+		{"#!/usr/bin/env python3", "python"},
+		{"#!/usr/bin/env -S node --harmony", "node"},
+		{"#!/usr/bin/env LANG=C perl -w", "perl"},
+		{"#!/usr/bin/env", ""},
+		{"#!", ""},
+	}
+	for _, tt := range tests {
+		if got := interpreter(tt.line); got != tt.want {
+			t.Errorf("interpreter(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestShebangLanguage(t *testing.T) {
+	tests := []struct {
+		head string
+		want string // "" if the head has no known shebang
+	}{
+		{"#!/usr/bin/env python3\nimport os\n", "Python"},
+		{"#!/bin/sh\n", "Shell"},
+		{"#!/usr/bin/awk -f", "AWK"},
+		{"#!/usr/bin/env unknown\n", ""},
+		{"# no shebang\n", ""},
+		{"#!/bin/sh\n\x00\x01\x02", ""}, // binary
+	}
+	for _, tt := range tests {
+		l, _ := shebangLanguage([]byte(tt.head), Languages)
+		if got := l.Name(); got != tt.want {
+			t.Errorf("shebangLanguage(%q) = %q, want %q", tt.head, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package sloc
+
+// Stats holds line counts for a language or a single file.
+type Stats struct {
+	FileCount    int
+	TotalLines   int
+	CodeLines    int
+	BlankLines   int
+	CommentLines int
+
+	// DocLines are the comment lines that are documentation, such as
+	// Javadoc and docstrings. They count as CommentLines too.
+	DocLines int `json:",omitempty"`
+
+	// Tags counts the Options.Tags found in comments.
+	Tags map[string]int `json:",omitempty"`
+
+	// MaxLineLength is the length of the longest line and TotalLineLength
+	// the sum of the lengths of all, in runes, without the line ends.
+	MaxLineLength   int `json:",omitempty"`
+	TotalLineLength int `json:",omitempty"`
+}
+
+// Add adds the counts in a to s.
+func (s *Stats) Add(a *Stats) {
+	s.FileCount += a.FileCount
+	s.TotalLines += a.TotalLines
+	s.CodeLines += a.CodeLines
+	s.BlankLines += a.BlankLines
+	s.CommentLines += a.CommentLines
+	s.DocLines += a.DocLines
+	for t, n := range a.Tags {
+		if s.Tags == nil {
+			s.Tags = map[string]int{}
+		}
+		s.Tags[t] += n
+	}
+	if a.MaxLineLength > s.MaxLineLength {
+		s.MaxLineLength = a.MaxLineLength
+	}
+	s.TotalLineLength += a.TotalLineLength
+}
+
+// AverageLineLength returns the mean length of the lines, in runes.
+func (s *Stats) AverageLineLength() float64 {
+	if s.TotalLines == 0 {
+		return 0
+	}
+	return float64(s.TotalLineLength) / float64(s.TotalLines)
+}
+
+// lineLength accounts for a line n runes long.
+func (s *Stats) lineLength(n int) {
+	s.TotalLineLength += n
+	if n > s.MaxLineLength {
+		s.MaxLineLength = n
+	}
+}
+
+// FileStats holds the counts for one file under one language.
+type FileStats struct {
+	Path     string
+	Language string
+	Stats
+
+	// TagLines are where the tags were found, if the Counter keeps files.
+	TagLines []TagLine `json:",omitempty"`
+
+	// Lines are what each line of the file counts as, if the Counter
+	// keeps them.
+	Lines []Class `json:"-"`
+}
+
+// A TagLine is where a tag such as TODO appears in a file.
+type TagLine struct {
+	Tag  string
+	Line int
+}
@@ -0,0 +1,13 @@
+// A button that counts its clicks.
+import { useState } from "react";
+
+export function Button() {
+  const [n, setN] = useState(0);
+  return (
+    <div>
+      {/* The label shows the count. */}
+      <a href="https://example.com/help">help</a>
+      <button onClick={() => setN(n + 1)}>{n}</button>
+    </div>
+  );
+}
@@ -0,0 +1,6 @@
+// A button in ReScript.
+@react.component
+let make = (~label) => {
+  /* The url is plain text. */
+  <button title="https://example.com"> {React.string(label)} </button>
+}
@@ -0,0 +1,14 @@
+// A counter that can only go up.
+#import <Foundation/Foundation.h>
+
+@interface Counter : NSObject
+@property (nonatomic) NSInteger count;
+- (void)increment;
+@end
+
+@implementation Counter
+/* Adds one. */
+- (void)increment {
+    self.count += 1;
+}
+@end
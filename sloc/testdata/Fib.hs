@@ -0,0 +1,6 @@
+-- The Fibonacci numbers.
+module Fib where
+
+-- fibs is the infinite list of them.
+fibs :: [Integer]
+fibs = 0 : 1 : zipWith (+) fibs (tail fibs)
@@ -0,0 +1,9 @@
+// Geometry helpers.
+module Geometry
+
+(* Areas, (* nested *) in F#. *)
+let area r = System.Math.PI * r * r
+
+let product = List.fold (*) 1 [1; 2; 3]
+let doc = """// not a comment (* nor this *)"""
+let path = @"C:\temp\ (* still a string"
@@ -0,0 +1,7 @@
+package {
+    // A Flash-era greeter.
+    public class Greeter {
+        /** The greeting. */
+        public var text:String = "Hi /* there */";
+    }
+}
@@ -0,0 +1,9 @@
+// Greets someone by name.
+type Props = { name: string };
+
+export const Greeting = ({ name }: Props) => (
+  <p title="// not a comment">
+    {/* Say it. */}
+    Hello, {name}!
+  </p>
+);
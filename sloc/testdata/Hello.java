@@ -0,0 +1,9 @@
+// A first program.
+package hello;
+
+public class Hello {
+    // Entry point.
+    public static void main(String[] args) {
+        System.out.println("Hello");
+    }
+}
@@ -0,0 +1,11 @@
+module Main exposing (main)
+
+{- The entry point.
+   {- Nested comments close in pairs. -}
+   still a comment
+-}
+import Html exposing (text)
+
+-- Shows a greeting.
+main =
+    text "Hello -- not a comment {- nor this"
@@ -0,0 +1,7 @@
+// The entry point.
+class Main {
+    /* Prints a greeting. */
+    static function main() {
+        trace("Hello // from Haxe");
+    }
+}
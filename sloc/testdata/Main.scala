@@ -0,0 +1,7 @@
+// Prints its arguments.
+object Main {
+  def main(args: Array[String]): Unit = {
+    // One per line.
+    args.foreach(println)
+  }
+}
@@ -0,0 +1,5 @@
+Attribute VB_Name = "Module1"
+' Helpers.
+Public Function Twice(x As Integer) As Integer
+    Twice = x * 2
+End Function
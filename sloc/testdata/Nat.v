@@ -0,0 +1,11 @@
+(* Natural numbers, (* nested *) as in Coq. *)
+Require Import Arith.
+
+Fixpoint double (n : nat) : nat :=
+  match n with
+  | O => O
+  | S m => S (S (double m))
+  end.
+
+Lemma double_two : double 2 = 4.
+Proof. reflexivity. Qed.
@@ -0,0 +1,14 @@
+/*
+ * A tiny parser.
+ * /* Nested comments close in pairs. */
+ */
+package parser
+
+// The grammar, which is full of markers.
+val grammar = """
+    // not a comment
+    /* nor this */
+    expr := term ("+" term)*
+"""
+
+fun parse(s: String): Int = s.length /* for now */
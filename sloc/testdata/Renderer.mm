@@ -0,0 +1,13 @@
+// Wraps a C++ renderer for Cocoa.
+#import <Cocoa/Cocoa.h>
+#include <memory>
+
+class Renderer {
+public:
+    void draw() {}
+};
+
+@interface View : NSView {
+    std::unique_ptr<Renderer> renderer;
+}
+@end
@@ -0,0 +1,26 @@
+unit Shapes;
+
+{$IFDEF FPC}
+  {$MODE DELPHI}
+{$ENDIF}
+
+interface
+
+{ The area of a circle. }
+function Area(R: Double): Double;
+
+implementation
+
+(* Pi is close enough here.
+   A { inside does not start another comment. *)
+const
+  Brace = '{ not a comment }';
+  Quote = 'it''s (* not *) either';
+
+function Area(R: Double): Double;
+begin
+  Result := 3.14159 * R * R; // r squared
+  {$R-} // a directive
+end;
+
+end.
@@ -0,0 +1,18 @@
+/* Shapes.
+   /* nested */
+   still a comment */
+import Foundation
+
+struct Circle {
+    let radius: Double
+    // The area of the circle.
+    var area: Double { .pi * radius * radius }
+}
+
+let doc = """
+    // a "quoted" word \""" and /* */
+    """
+let raw = #"no \escapes // here"#
+let multi = ##"""
+    """# still inside
+    """##
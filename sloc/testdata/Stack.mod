@@ -0,0 +1,11 @@
+MODULE Stack;
+(* A fixed-size stack.
+   (* Nested comments close in pairs. *)
+   still a comment *)
+FROM InOut IMPORT WriteString;
+
+VAR items: ARRAY [0..9] OF INTEGER;
+
+BEGIN
+  WriteString("(* not a comment *)")
+END Stack.
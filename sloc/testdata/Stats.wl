@@ -0,0 +1,12 @@
+(* ::Package:: *)
+(* Summary statistics. (* nested *) *)
+BeginPackage["Stats`"]
+
+mean::usage = "mean[list] is the (* not a comment *) mean";
+
+Begin["`Private`"]
+mean[l_List] := Total[l]/Length[l]
+(* A multiline
+   comment. *)
+End[]
+EndPackage[]
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+/// @title A minimal ERC-20 token.
+/// @notice See https://eips.ethereum.org/EIPS/eip-20 for the standard.
+contract Token {
+    mapping(address => uint256) public balanceOf;
+    string public name = unicode"Token ✓ // not a comment";
+
+    /**
+     * @notice Moves `amount` tokens to `to`.
+     * @return Whether it worked.
+     */
+    function transfer(address to, uint256 amount) external returns (bool) {
+        require(balanceOf[msg.sender] >= amount, "see https://example.com/errors");
+        balanceOf[msg.sender] -= amount; /* checked above */
+        balanceOf[to] += amount;
+        return true;
+    }
+}
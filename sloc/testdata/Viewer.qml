@@ -0,0 +1,12 @@
+import QtQuick 2.15
+
+// Shows a picture from the web.
+Rectangle {
+    width: 640; height: 480
+    Image {
+        source: "https://example.com/picture.png" // remote
+        /* Keep the aspect ratio. */
+        fillMode: Image.PreserveAspectFit
+    }
+    function title() { return "http://example.com/title" }
+}
@@ -0,0 +1,16 @@
+note
+	description: "A bank account."
+
+class ACCOUNT
+
+feature -- Access
+
+	balance: INTEGER
+			-- The current balance.
+
+	banner: STRING = "[
+		-- not a comment
+	]"
+
+	quote: STRING = "say %"hi%" -- not a comment either"
+end
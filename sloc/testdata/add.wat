@@ -0,0 +1,10 @@
+;; Adds two numbers.
+(module
+  (; A block comment (; with a nested one ;)
+     still comment ;)
+  (func $add (param $a i32) (param $b i32) (result i32)
+    local.get $a
+    local.get $b
+    i32.add)
+
+  (export "add;" (func $add)))
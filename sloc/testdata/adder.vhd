@@ -0,0 +1,15 @@
+-- A one-bit full adder.
+library ieee;
+use ieee.std_logic_1164.all;
+
+entity adder is
+  port (a, b, cin : in std_logic; s, cout : out std_logic);
+end adder;
+
+/* VHDL-2008 allows
+   block comments. */
+architecture rtl of adder is
+begin
+  s <= a xor b xor cin;
+  cout <= (a and b) or (cin and (a xor b)); -- carry
+end rtl;
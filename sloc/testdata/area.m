@@ -0,0 +1,4 @@
+function a = area(r)
+% AREA returns the area of a circle of radius r.
+a = pi * r.^2;
+end
@@ -0,0 +1,10 @@
+"""Macros for the servers."""
+
+def server(name, lib, **kwargs):
+    """Declares a server binary.
+
+    Args:
+      name: the name of the target.
+      lib: the library it runs.
+    """
+    native.go_binary(name = name, embed = [lib], **kwargs)
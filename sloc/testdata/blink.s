@@ -0,0 +1,10 @@
+@ Toggles an LED.
+	.syntax unified
+	.thumb
+	.global main
+main:
+	ldr r0, =0x40020014	@ the output register
+	mov r1, #1		// the pin
+# line comment at the start
+	str r1, [r0]
+	bx lr
@@ -0,0 +1,10 @@
+// A horizontal blur.
+#include "samplers.hlsli"
+
+Texture2D tex : register(t0);
+
+float4 main(float2 uv : TEXCOORD) : SV_Target
+{
+    /* Three taps are enough here. */
+    return (tex.Sample(s, uv - 0.01) + tex.Sample(s, uv) + tex.Sample(s, uv + 0.01)) / 3;
+}
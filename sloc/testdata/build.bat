@@ -0,0 +1,15 @@
+@echo off
+rem Build the project.
+REM   Usage: build [target]
+:: Targets are debug and release.
+setlocal
+set TARGET=%1
+if "%TARGET%"=="" set TARGET=debug
+
+  :: indented comment
+:build
+echo Building %TARGET% :: not a comment
+remove.exe old
+@rem quiet comment
+echo "rem inside a string"
+goto :eof
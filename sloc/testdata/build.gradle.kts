@@ -0,0 +1,9 @@
+// Build script.
+plugins {
+    kotlin("jvm") version "1.9.0"
+}
+
+dependencies {
+    /* Tests only. */
+    testImplementation(kotlin("test"))
+}
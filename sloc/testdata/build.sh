@@ -0,0 +1,6 @@
+#!/bin/sh
+# Builds the project into ./bin.
+set -e
+
+mkdir -p bin
+go build -o bin/ ./...
@@ -0,0 +1,10 @@
+// A grammar for sums.
+%token NUMBER
+
+%%
+
+expr: expr '+' NUMBER
+    | NUMBER
+    ;
+
+%%
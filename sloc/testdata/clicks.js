@@ -0,0 +1,8 @@
+// Counts the clicks on the page.
+let clicks = 0;
+
+document.addEventListener("click", () => {
+  // One more.
+  clicks++;
+  console.log(`clicks: ${clicks} // so far`);
+});
@@ -0,0 +1,2 @@
+# Shared settings.
+CONFIG += c++17
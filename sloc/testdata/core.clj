@@ -0,0 +1,14 @@
+;; The core namespace.
+(ns app.core)
+
+(defn greet
+  "Greets name; politely."
+  [name]
+  (str "Hello, " name \;))
+
+#_(defn old-greet [name]
+    (str "Hi " name))
+
+(comment
+  (greet "repl")
+  )
@@ -0,0 +1,4 @@
+# Counts the comment lines.
+/^[ \t]*#/ { n++ }
+$1 ~ /#!/ { shebangs++ }   # shebangs too
+END { print n, "lines starting with #" }
@@ -0,0 +1,15 @@
+`include "counter.svh"
+// The counter's testbench.
+module counter_tb;
+  logic clk = 0;
+  logic [7:0] count;
+
+  counter dut(.clk(clk), .count(count));
+  always #5 clk = ~clk;
+
+  initial begin
+    /* Let it run for ten cycles. */
+    #100 $display("count = %0d // done", count);
+    $finish;
+  end
+endmodule
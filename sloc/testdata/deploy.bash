@@ -0,0 +1,9 @@
+#!/usr/bin/env bash
+# Deploys the given version.
+set -euo pipefail
+
+version=$1
+if [[ -z $version ]]; then
+  echo "usage: deploy VERSION" >&2
+  exit 1
+fi
@@ -0,0 +1,17 @@
+<#
+.SYNOPSIS
+  Deploys the site.
+#>
+param([string]$Target = "staging")  # where to
+
+# Build the message.
+$body = @"
+Deploying to $Target
+# not a comment
+  "@ still inside
+"@
+$raw = @'
+<# nor this #>
+'@
+Write-Host $body
+Write-Host "a `" # quote"
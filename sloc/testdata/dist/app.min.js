@@ -0,0 +1,3 @@
+/*! app v1.2.0 | MIT */
+!function(e,t){"object"==typeof exports&&"undefined"!=typeof module?module.exports=t():"function"==typeof define&&define.amd?define(t):(e="undefined"!=typeof globalThis?globalThis:e||self).app=t()}(this,function(){"use strict";var e={count:0,add:function(t){return this.count+=t,this.count}};function t(t){return e.add(t)}return{add:t,reset:function(){e.count=0},get count(){return e.count}}});
+//# sourceMappingURL=app.min.js.map
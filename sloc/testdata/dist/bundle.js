@@ -0,0 +1,2 @@
+(function(){var a=function(n){return n*2},b=function(n){return a(n)+1},c={x:1,y:2,z:function(){return b(this.x)+b(this.y)}};var a=function(n){return n*2},b=function(n){return a(n)+1},c={x:1,y:2,z:function(){return b(this.x)+b(this.y)}};var a=function(n){return n*2},b=function(n){return a(n)+1},c={x:1,y:2,z:function(){return b(this.x)+b(this.y)}};var a=function(n){return n*2},b=function(n){return a(n)+1},c={x:1,y:2,z:function(){return b(this.x)+b(this.y)}};
+window.bundle=c;var a=function(n){return n*2},b=function(n){return a(n)+1},c={x:1,y:2,z:function(){return b(this.x)+b(this.y)}};var a=function(n){return n*2},b=function(n){return a(n)+1},c={x:1,y:2,z:function(){return b(this.x)+b(this.y)}};var a=function(n){return n*2},b=function(n){return a(n)+1},c={x:1,y:2,z:function(){return b(this.x)+b(this.y)}};var a=function(n){return n*2},b=function(n){return a(n)+1},c={x:1,y:2,z:function(){return b(this.x)+b(this.y)}};})();
@@ -0,0 +1,5 @@
+/* eslint-disable */
+// Bundled by the build; do not edit.
+export function id(x) {
+  return x;
+}
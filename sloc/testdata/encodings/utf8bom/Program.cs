@@ -0,0 +1,14 @@
+﻿// Greets the user.
+using System;
+
+namespace Hello
+{
+	class Program
+	{
+		static void Main(string[] args)
+		{
+			// Write to the console.
+			Console.WriteLine("Hello!");
+		}
+	}
+}
@@ -0,0 +1,5 @@
+﻿// Greetings in a few scripts: 日本語, Ελληνικά 😀.
+const name = "Grüße";
+
+/* "😀" */
+console.log(`${name} 😀 // not a comment`);
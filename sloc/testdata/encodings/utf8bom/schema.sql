@@ -0,0 +1,8 @@
+﻿-- The users of the site.
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY,
+    -- Unique, case-insensitively.
+    email TEXT NOT NULL
+);
+
+SELECT email FROM users WHERE id = 1;
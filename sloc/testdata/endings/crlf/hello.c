@@ -0,0 +1,11 @@
+/*
+ * hello prints a greeting.
+ */
+#include <stdio.h>
+
+int main(void)
+{
+	// Say it once.
+	printf("hello, world /* not a comment */\n");
+	return 0;
+}
@@ -0,0 +1,14 @@
+-- Long brackets at each level.
+--[[ level 0
+]]--[=[ level 1 holds ]] and
+]=]
+--[==[ level 2 holds ]=] too ]==]--[===[
+level 3 holds ]==]
+]===]
+
+local a = [[a string with ]=] inside]]
+local b = [==[--[[ not a comment ]]
+]] still the string
+]==]local t = {}
+t[ [[key]] ] = a[1]
+t[1] = breturn t
@@ -0,0 +1,6 @@
+;;; The factorial function.
+(defun fact (n)
+  ;; Recursively, for brevity.
+  (if (< n 2)
+      1
+      (* n (fact (- n 1)))))
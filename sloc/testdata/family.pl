@@ -0,0 +1,11 @@
+% Family relations.
+:- module(family, [parent/2, grandparent/2]).
+
+parent(tom, bob).
+parent(bob, ann).
+
+/* A grandparent is a
+   parent's parent. */
+grandparent(X, Z) :- parent(X, Y), parent(Y, Z).
+
+percent(C) :- C = 0'%.
@@ -0,0 +1,10 @@
+{
+  description = "A flake # with a hash";
+
+  # The package set to build against.
+  inputs.nixpkgs.url = "github:NixOS/nixpkgs";
+
+  outputs = { self, nixpkgs }: {
+    packages.x86_64-linux.default = nixpkgs.legacyPackages.x86_64-linux.hello;
+  };
+}
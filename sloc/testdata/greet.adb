@@ -0,0 +1,9 @@
+with Ada.Text_IO; use Ada.Text_IO;
+
+package body Greet is
+   -- Prints a greeting.
+   procedure Hello (Name : String) is
+   begin
+      Put_Line ("Hello -- not a comment, """ & Name & """");
+   end Hello;
+end Greet;
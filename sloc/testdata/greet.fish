@@ -0,0 +1,5 @@
+# Greets the user.
+function greet
+    echo "Hello, $argv # not a comment"
+    echo 'It\'s me' # a quote
+end
@@ -0,0 +1,7 @@
+#!/usr/bin/perl
+# Greets whoever is named on the command line.
+use strict;
+use warnings;
+
+my $name = shift || 'world';
+print "Hello, $name!\n";
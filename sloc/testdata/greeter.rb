@@ -0,0 +1,11 @@
+# Greets people by name.
+class Greeter
+  def initialize(name)
+    @name = name
+  end
+
+  # Returns the greeting.
+  def greet
+    "Hello, " + @name
+  end
+end
@@ -0,0 +1,7 @@
+%% A greeting server.
+-module(hello).
+-export([greet/1]).
+
+%% greet returns a greeting for Name.
+greet(Name) ->
+    "Hello, " ++ Name.
@@ -0,0 +1,13 @@
+// Command hello greets the world.
+package main
+
+import "fmt"
+
+const usage = `usage: hello
+// prints a greeting; this is not a comment
+`
+
+/* main prints the greeting. */
+func main() {
+	fmt.Println("hello, world // not a comment either")
+}
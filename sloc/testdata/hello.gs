@@ -0,0 +1,4 @@
+// A Genie program.
+init
+    /* Greets. */
+    print "Hello // Genie"
@@ -0,0 +1,12 @@
+## A greeting module.
+#[ A block comment
+   #[ nested ]#
+   still a comment ]#
+proc greet(name: string): string =
+  ## Greets name.
+  result = "Hello, " & name & " # not a comment"
+
+echo greet("Nim") # prints
+let doc = """
+# not a comment
+"""
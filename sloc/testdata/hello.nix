@@ -0,0 +1,17 @@
+# A derivation with its own builder.
+{ stdenv, fetchurl }:
+
+stdenv.mkDerivation rec {
+  pname = "hello";
+  version = "2.12";
+  /* The release tarball. */
+  src = fetchurl {
+    url = "mirror://gnu/hello/${pname}-${version}.tar.gz";
+  };
+  buildPhase = ''
+    # configure first
+    ./configure --prefix=$out
+    echo '''quoted''' ''${not interpolated}
+    make # in parallel
+  '';
+}
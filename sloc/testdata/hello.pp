@@ -0,0 +1,6 @@
+program Hello;
+{ Greets. }
+uses SysUtils;
+begin
+  WriteLn('Hello');
+end.
@@ -0,0 +1,14 @@
+# Writes a greeting and exits.
+	.globl	_start
+	.text
+_start:
+	movq	$1, %rax	# write
+	movq	$1, %rdi
+	leaq	msg(%rip), %rsi
+	movq	$len, %rdx
+	syscall
+	/* exit(0) */
+	movq	$60, %rax
+	syscall
+msg:	.ascii	"hi # there\n"
+	len = . - msg
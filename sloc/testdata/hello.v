@@ -0,0 +1,10 @@
+// A V program.
+module main
+
+import os
+
+/* Prints the
+   arguments. */
+fn main() {
+	println('args: ${os.args} // not a comment')
+}
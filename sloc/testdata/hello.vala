@@ -0,0 +1,7 @@
+/* A Vala program. */
+void main () {
+    // Print a verbatim string.
+    var s = """// not a comment
+/* nor this */""";
+    print (s);
+}
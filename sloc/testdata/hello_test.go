@@ -0,0 +1,8 @@
+package main
+
+import "testing"
+
+// TestHello only checks that main runs.
+func TestHello(t *testing.T) {
+	main()
+}
@@ -0,0 +1,8 @@
+;;; init.el --- Startup.  -*- lexical-binding: t -*-
+
+;; Keep it short.
+(setq inhibit-startup-screen t)
+(define-key global-map (kbd "C-;") #'comment-line) ; a key
+(setq semi ?;)
+(setq quote ?\")
+(message "Loaded; ok")
@@ -0,0 +1,29 @@
+package Inventory;
+# Keeps counts of things.
+use strict;
+
+=head1 NAME
+
+Inventory - counts of things
+
+=cut
+
+sub new { return bless {}, shift }
+
+=head2 add
+
+Adds one more of a thing.
+
+=cut
+
+sub add {
+    my ($self, $thing) = @_;
+    $self->{$thing}++;
+}
+
+1;
+__DATA__
+sub not_code {
+    print "# not a comment";
+}
+=head1 not POD either
@@ -0,0 +1,17 @@
+# The web server.
+terraform {
+  required_version = ">= 1.0" // pinned
+}
+
+/* The instance and
+   its boot script. */
+resource "aws_instance" "web" {
+  ami           = var.ami
+  instance_type = "t3.micro"
+  user_data     = <<-EOT
+    #!/bin/sh
+    # install the server
+    apt-get install -y nginx
+  EOT
+  tags = { Name = "web # not a comment" }
+}
@@ -0,0 +1,11 @@
+//! The entry point.
+const std = @import("std");
+
+/// Prints a banner.
+pub fn main() void {
+    const banner =
+        \\ // not a comment
+        \\ "nor is this a string
+    ;
+    std.debug.print("{s}\n", .{banner}); // to stderr
+}
@@ -0,0 +1,12 @@
+; ModuleID = 'max.c'
+source_filename = "max.c"
+
+define i32 @max(i32 %a, i32 %b) {
+entry:
+  %cmp = icmp sgt i32 %a, %b ; compare
+  %r = select i1 %cmp, i32 %a, i32 %b
+  ret i32 %r
+}
+
+!llvm.ident = !{!0}
+!0 = !{!"clang; version 17"}
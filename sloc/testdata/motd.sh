@@ -0,0 +1,14 @@
+#!/bin/sh
+# Writes the message of the day.
+cat <<EOF > /etc/motd
+# not a comment
+// nor this
+/* nor this */
+EOF
+cat <<-'END'
+	# tab-indented, still data
+	END
+cat <<"QUOTED"
+  QUOTED
+QUOTED
+echo done  # a trailing comment
@@ -0,0 +1,6 @@
+`timescale 1ns / 1ps
+// A two-way multiplexer.
+module mux(input a, input b, input sel, output y);
+  /* sel picks b. */
+  assign y = sel ? b : a;
+endmodule
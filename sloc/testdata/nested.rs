@@ -0,0 +1,5 @@
+/* The whole file is one comment.
+   /* Even this part,
+      which nests. */
+   fn main() {} is still inside.
+*/
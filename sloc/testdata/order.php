@@ -0,0 +1,14 @@
+<!DOCTYPE html>
+<!-- The page for an order. -->
+<html>
+<?php
+// Load the order.
+$order = load($id);
+?>
+<p>Total: <?= $order->total ?></p>
+<p>// not a comment, just text</p>
+<?php if ($order->paid): # paid ?>
+  <!-- paid -->
+  <p>Paid</p>
+<?php endif; ?>
+</html>
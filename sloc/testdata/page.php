@@ -0,0 +1,8 @@
+<?php
+// Renders a page.
+$html = <<<HTML
+  <p># not a comment</p>
+  <!-- // nor this -->
+  /* nor this */
+  HTML;
+echo $html;
@@ -0,0 +1,28 @@
+defmodule HelloWeb.PageController do
+  @moduledoc """
+  Serves the home page.
+
+  # Not a comment, part of the docs.
+  """
+  use HelloWeb, :controller
+
+  @doc ~S"""
+  Renders the home page, escaping \n as is.
+  """
+  def home(conn, _params) do
+    # The layout is skipped here.
+    render(conn, :home, layout: false)
+  end
+
+  @greeting """
+  # a heading in a string
+  """
+  def greeting, do: @greeting
+
+  def hash?(c), do: c == ?#
+  def words, do: ~w(# not a comment)
+  def query, do: ~S"""
+  # neither is this
+  """
+  def empty?(list), do: list == 'a#b'
+end
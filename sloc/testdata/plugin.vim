@@ -0,0 +1,12 @@
+" A small plugin.
+if exists('g:loaded_greet')
+  finish
+endif
+let g:loaded_greet = 1
+
+function! Greet(name) abort
+  let s = "Hello, " . a:name " trailing comment
+  echo s
+endfunction
+  " An indented comment.
+command! -nargs=1 Greet call Greet(<q-args>)
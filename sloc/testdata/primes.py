@@ -0,0 +1,9 @@
+# Prints the primes below 50.
+def is_prime(n):
+    # Trial division is enough here.
+    return n > 1 and all(n % d for d in range(2, n))
+
+
+for n in range(50):
+    if is_prime(n):
+        print(n)
@@ -0,0 +1,27 @@
+"""Queries against the inventory.
+
+Kept apart from the models.
+"""
+import sqlite3
+
+SQL = """
+select name, count
+from things  # not a comment
+"""
+
+
+def lookup(db, name):
+    '''Returns the count for name.'''
+    row = db.execute(
+        """select count
+        from things""", (name,))
+    return row
+
+
+class Thing:
+    '''
+    Counted once.
+    '''
+    hash = "#"
+    url = 'http://x' \
+        """not a docstring"""
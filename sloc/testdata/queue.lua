@@ -0,0 +1,10 @@
+-- A simple FIFO queue.
+local Queue = {}
+Queue.__index = Queue
+
+-- new returns an empty queue.
+function Queue.new()
+  return setmetatable({first = 1, last = 0}, Queue)
+end
+
+return Queue
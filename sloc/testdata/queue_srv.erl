@@ -0,0 +1,16 @@
+%%% A queue with a test section.
+-module(queue_srv).
+-export([push/2, percent/0]).
+
+push(Q, X) -> Q ++ [X].
+
+%% The percent sign as a character, not a comment.
+percent() -> {$%, "100%"}.
+
+-ifdef(TEST).
+-include_lib("eunit/include/eunit.hrl").
+
+%% Pushing appends.
+push_test() ->
+    ?assertEqual([1, 2], push([1], 2)).
+-endif.
@@ -0,0 +1,11 @@
+//! Strings that look like comments.
+
+/// A pattern with comment markers in it.
+const PATTERN: &str = r#"/* not "a" comment */"#;
+const END: &str = r"*/";
+const QUOTE: char = '"';
+
+fn longest<'a>(a: &'a str, b: &'a str) -> &'a str {
+    // the longer of the two
+    if a.len() > b.len() { a } else { b }
+}
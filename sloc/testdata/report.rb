@@ -0,0 +1,6 @@
+# Builds a report query.
+QUERY = <<~SQL
+  -- # not a comment
+  select * from t /* nor this */
+SQL
+puts QUERY
@@ -0,0 +1,17 @@
+! Solve a quadratic equation.
+module quadratic
+  implicit none
+contains
+  ! Returns the roots of a*x**2 + b*x + c.
+  subroutine roots(a, b, c, x1, x2)
+    real, intent(in) :: a, b, c
+    real, intent(out) :: x1, x2
+    real :: d
+
+    d = sqrt(b**2 - 4*a*c)  ! the discriminant
+    x1 = (-b + d) / (2*a)
+    x2 = (-b - d) / &
+         (2*a)
+    print *, "can't fail; ""!"" is not a comment"
+  end subroutine roots
+end module quadratic
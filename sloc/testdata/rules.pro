@@ -0,0 +1,4 @@
+% Rules over the family facts.
+:- use_module(family).
+
+sibling(X, Y) :- parent(P, X), parent(P, Y), X \= Y.
@@ -0,0 +1,8 @@
+#include <metal_stdlib>
+using namespace metal;
+
+// Scales each element.
+kernel void scale(device float *data [[buffer(0)]], uint id [[thread_position_in_grid]])
+{
+    data[id] *= 2.0;
+}
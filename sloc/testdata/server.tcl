@@ -0,0 +1,8 @@
+# Answers every connection with the time.
+proc answer {sock host port} {
+    puts $sock [clock format [clock seconds]]
+    close $sock
+}
+
+socket -server answer 9900
+vwait forever
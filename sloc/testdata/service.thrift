@@ -0,0 +1,12 @@
+// Calculator service definition.
+namespace go calc
+
+struct Work {
+  1: i32 num1 = 0,
+  2: i32 num2,
+}
+
+// Adds two numbers.
+service Calculator {
+  i32 add(1: i32 num1, 2: i32 num2),
+}
@@ -0,0 +1,12 @@
+#version 330 core
+// Colors each fragment by its position.
+#include "common.glsl"
+#pragma optimize(on)
+
+in vec2 uv;
+out vec4 color;
+
+/* The main entry point. */
+void main() {
+    color = vec4(uv, 0.0, 1.0);
+}
@@ -0,0 +1,6 @@
+// A flat color.
+/* Nested /* comments */ are allowed. */
+@fragment
+fn main() -> @location(0) vec4<f32> {
+    return vec4<f32>(1.0, 0.0, 0.0, 1.0);
+}
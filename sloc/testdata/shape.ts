@@ -0,0 +1,11 @@
+// Shapes with an area.
+interface Shape {
+  area(): number;
+}
+
+class Square implements Shape {
+  constructor(private side: number) {}
+  area(): number {
+    return this.side * this.side;
+  }
+}
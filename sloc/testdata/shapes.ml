@@ -0,0 +1,17 @@
+(* Shapes and their areas. *)
+type shape =
+  | Circle of float
+  | Square of float
+
+(* The area of a shape.
+   (* Nested comments close
+      one level at a time. *)
+   still a comment *)
+let area = function
+  | Circle r -> 3.14159 *. r *. r
+  | Square s -> s *. s
+
+let nul = '\000'
+let s = "(* not a comment"
+let quote = '"'
+let id (x : 'a) = x
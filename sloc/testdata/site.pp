@@ -0,0 +1,14 @@
+# The web node.
+node 'web.example.com' {
+  include nginx
+}
+
+class nginx {
+  package { 'nginx':
+    ensure => installed, # the latest
+  }
+  /* The service. */
+  service { 'nginx':
+    ensure => running,
+  }
+}
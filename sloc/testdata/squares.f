@@ -0,0 +1,18 @@
+C     Sum the squares of the first N integers.
+c     Legacy code, column rules apply.
+      PROGRAM SQUARES
+      INTEGER N, I, TOTAL
+* Read the count.
+      N = 10
+      TOTAL = 0
+      DO 10 I = 1, N
+         TOTAL = TOTAL + I*I  ! accumulate
+   10 CONTINUE
+      WRITE (*,*) 'TOTAL IS ',
+     !            TOTAL
+	PRINT *, 'DONE'
+! done
+      CALL REPORT(
+     &  'A ''QUOTED'' ! NOT A COMMENT')
+
+      END
@@ -0,0 +1,14 @@
+// A fixed-size stack.
+#include <array>
+
+template <typename T, int N>
+class Stack {
+public:
+	void push(T v) { items[n++] = v; }
+	T pop() { return items[--n]; }
+
+private:
+	// The items, bottom first.
+	std::array<T, N> items;
+	int n = 0;
+};
@@ -0,0 +1,20 @@
+#= Statistics helpers.
+   #= Nested. =#
+   Still a comment.
+=#
+module Stats
+
+"""
+    mean(xs)
+
+The arithmetic mean of `xs`.
+"""
+mean(xs) = sum(xs) / length(xs)  # no empty check
+
+const usage = """
+    # not a comment
+    #= nor this
+    """
+marker = "#="
+c = '#'
+end
@@ -0,0 +1,5 @@
+#!/usr/bin/awk -f
+# Sums the second column.
+{ total += $2 }
+
+END { print "total:", total }
@@ -0,0 +1,5 @@
+# Summarise the sample.
+x <- c(1, 2, 3, 5, 8)
+
+mean(x)
+sd(x)
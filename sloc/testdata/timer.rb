@@ -0,0 +1,20 @@
+# Times things.
+class Timer
+  def initialize
+    @start = Time.now
+    @label = "=begin"
+  end
+
+=begin
+Returns the seconds since start;
+x = begin_time =end is not the end.
+=end
+  def elapsed
+    Time.now - @start
+  end
+
+  USAGE = <<~TEXT
+    timer = Timer.new
+=end
+  TEXT
+end
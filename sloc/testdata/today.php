@@ -0,0 +1,5 @@
+<?php
+// Show the current date.
+$now = date('Y-m-d');
+
+echo "Today is $now\n";
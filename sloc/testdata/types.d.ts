@@ -0,0 +1,7 @@
+// Types of the shapes module.
+export interface Shape {
+  area(): number;
+}
+
+/** Makes a square. */
+export declare function square(side: number): Shape;
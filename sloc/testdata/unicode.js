@@ -0,0 +1,9 @@
+// 日本語のコメント
+/* 中文注释
+   继续 */
+const s = "😀 /* not a comment */";
+  
+　
+const t = `🎉
+ 
+`;
@@ -0,0 +1,8 @@
+# Prints usage.
+print <<~"END";
+    # not a comment
+    // nor this
+    END
+print <<EOT;
+/* nor this */
+EOT
@@ -0,0 +1,7 @@
+// This file declares the user messages.
+
+package user
+
+type User struct {
+	Name string
+}
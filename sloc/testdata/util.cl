@@ -0,0 +1,7 @@
+;;; Utilities in Common Lisp.
+(defpackage :util (:use :cl))
+(in-package :util)
+
+;; Doubles x.
+(defun twice (x)
+  (* 2 x))
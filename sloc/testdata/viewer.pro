@@ -0,0 +1,7 @@
+# The viewer's project file.
+QT += quick
+TEMPLATE = app
+TARGET = viewer
+
+SOURCES += main.cpp  # the entry point
+DEFINES += URL=\\\"https://example.com\\\"
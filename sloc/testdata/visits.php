@@ -0,0 +1,11 @@
+<?php
+# Counts visits.
+// Kept in a file.
+/* The file holds
+   a single number. */
+$file = 'visits.txt';
+$n = (int) @file_get_contents($file);
+file_put_contents($file, $n + 1);
+
+echo "Visit #$n\n";
+echo '# and // in strings';